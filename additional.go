@@ -0,0 +1,218 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/OlegSchmidt/soup"
+)
+
+const (
+	// entries of the additional information block, independent of the language of the page
+	additionalUpdated             = "updated"
+	additionalReleased            = "released"
+	additionalSize                = "size"
+	additionalInstalls            = "installs"
+	additionalCurrentVersion      = "current version"
+	additionalRequiresAndroid     = "requires android"
+	additionalContentRating       = "content rating"
+	additionalInteractiveElements = "interactive elements"
+	additionalInAppProducts       = "in-app products"
+	additionalPermissions         = "permissions"
+	additionalReport              = "report"
+	additionalOfferedBy           = "offered by"
+	additionalDeveloper           = "developer"
+	// text of the link to the privacy policy inside of the "developer" entry
+	additionalPrivacyPolicy = "privacy policy"
+)
+
+// returns the values of the entries of the additional information block (updated, size, installs etc.) keyed by
+// their label, the labels are translated into the entries above so that the order of the entries doesn't matter
+func getMainInformationBlockAdditionalEntries(document soup.Root, selectors SelectorProfile, locale Locale, property string) (map[string]soup.Root, error) {
+	informationAdditionalEntries := map[string]soup.Root{}
+
+	informationBlockAdditionalChildren, informationBlockAdditionalChildrenError := getMainInformationBlockAdditionalChildren(document, selectors, property)
+	if informationBlockAdditionalChildrenError != nil {
+		return informationAdditionalEntries, informationBlockAdditionalChildrenError
+	}
+	for _, informationBlockAdditionalChild := range informationBlockAdditionalChildren {
+		entryChildren := informationBlockAdditionalChild.Children()
+		if len(entryChildren) >= 2 {
+			if entry, known := locale.translateLabel(entryChildren[0].Text()); known {
+				informationAdditionalEntries[entry] = entryChildren[1]
+			}
+		}
+	}
+
+	return informationAdditionalEntries, nil
+}
+
+// returns the value of the entry of the additional information block, for example additionalUpdated
+func getMainInformationBlockAdditionalEntry(document soup.Root, selectors SelectorProfile, locale Locale, property string, entry string) (soup.Root, error) {
+	var informationAdditionalEntry soup.Root
+	var informationAdditionalEntryError error = nil
+
+	informationAdditionalEntries, informationAdditionalEntriesError := getMainInformationBlockAdditionalEntries(document, selectors, locale, property)
+	if informationAdditionalEntriesError == nil {
+		entryValue, exists := informationAdditionalEntries[entry]
+		if exists {
+			informationAdditionalEntry = entryValue
+		} else {
+			informationAdditionalEntryError = errors.New(property + " : <div class=\"" + selectors.ClassMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" doesn't contain the entry \"" + entry + "\"")
+		}
+	} else {
+		informationAdditionalEntryError = informationAdditionalEntriesError
+	}
+
+	return informationAdditionalEntry, informationAdditionalEntryError
+}
+
+// returns the text of the last span of the entry of the additional information block
+func getMainInformationBlockAdditionalEntryText(document soup.Root, selectors SelectorProfile, locale Locale, property string, entry string) (string, error) {
+	informationAdditionalEntry, informationAdditionalEntryError := getMainInformationBlockAdditionalEntry(document, selectors, locale, property, entry)
+	if informationAdditionalEntryError != nil {
+		return "", informationAdditionalEntryError
+	}
+
+	entryElements := informationAdditionalEntry.FindAll(span)
+	if len(entryElements) == 0 {
+		return "", errors.New(property + " : entry \"" + entry + "\" of <div class=\"" + selectors.ClassMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" should contain at least one span at lower levels")
+	}
+	entryText := strings.TrimSpace(entryElements[len(entryElements)-1].Text())
+	if entryText == "" {
+		return "", errors.New(property + " : last span of entry \"" + entry + "\" of <div class=\"" + selectors.ClassMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" is empty")
+	}
+
+	return entryText, nil
+}
+
+// returns the text like getMainInformationBlockAdditionalEntryText, but an empty text without error if the page doesn't
+// list the entry, apps without in-app products for example don't have the entry "in-app products"
+func getMainInformationBlockAdditionalOptionalEntryText(document soup.Root, selectors SelectorProfile, locale Locale, property string, entry string) (string, error) {
+	informationAdditionalEntries, informationAdditionalEntriesError := getMainInformationBlockAdditionalEntries(document, selectors, locale, property)
+	if informationAdditionalEntriesError != nil {
+		return "", informationAdditionalEntriesError
+	}
+	if _, exists := informationAdditionalEntries[entry]; !exists {
+		return "", nil
+	}
+
+	return getMainInformationBlockAdditionalEntryText(document, selectors, locale, property, entry)
+}
+
+// returns the innermost divs of the entry value, each of them holds a line like the email address of the developer
+func getEntryLines(entryValue soup.Root) []soup.Root {
+	var lines []soup.Root
+	for _, line := range entryValue.FindAll(div) {
+		if len(line.FindAll(div)) == 0 {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// returns the size of the app, for example "85M" or "Varies with device"
+func getSize(document soup.Root, selectors SelectorProfile, locale Locale) (string, error) {
+	return getMainInformationBlockAdditionalEntryText(document, selectors, locale, "size", additionalSize)
+}
+
+// returns the day the app was released, zero if the page doesn't show it
+func getReleaseDate(document soup.Root, selectors SelectorProfile, locale Locale) (*time.Time, error) {
+	releaseDateString, releaseDateStringError := getMainInformationBlockAdditionalOptionalEntryText(document, selectors, locale, "releaseDate", additionalReleased)
+	if releaseDateStringError != nil || releaseDateString == "" {
+		return nil, releaseDateStringError
+	}
+	releaseDate, releaseDateError := locale.parseDate(releaseDateString)
+	if releaseDateError != nil {
+		return nil, errors.New("releaseDate : entry \"" + additionalReleased + "\" doesn't contain a date : " + releaseDateError.Error())
+	}
+
+	return &releaseDate, nil
+}
+
+// returns the reasons of the content rating, for example "Digital Purchases" or "Mild Violence"
+func getContentRatingDescriptors(document soup.Root, selectors SelectorProfile, locale Locale) ([]string, error) {
+	contentRatingDescriptors := []string{}
+
+	contentRatingEntry, contentRatingEntryError := getMainInformationBlockAdditionalEntry(document, selectors, locale, "contentRatingDescriptors", additionalContentRating)
+	if contentRatingEntryError != nil {
+		return contentRatingDescriptors, contentRatingEntryError
+	}
+	// the first line is the content rating itself, the descriptors follow in lines of their own
+	contentRatingLines := getEntryLines(contentRatingEntry)
+	for position, contentRatingLine := range contentRatingLines {
+		if line := strings.TrimSpace(contentRatingLine.Text()); line != "" && position > 0 && len(contentRatingLine.Children()) == 0 {
+			contentRatingDescriptors = append(contentRatingDescriptors, line)
+		}
+	}
+
+	return contentRatingDescriptors, nil
+}
+
+// returns the interactive elements of the app, for example "Users Interact" or "Shares Location", most apps don't have
+// any
+func getInteractiveElements(document soup.Root, selectors SelectorProfile, locale Locale) ([]string, error) {
+	interactiveElements := []string{}
+
+	interactiveElementsString, interactiveElementsStringError := getMainInformationBlockAdditionalOptionalEntryText(document, selectors, locale, "interactiveElements", additionalInteractiveElements)
+	if interactiveElementsStringError != nil {
+		return interactiveElements, interactiveElementsStringError
+	}
+	for _, interactiveElement := range strings.Split(interactiveElementsString, ",") {
+		if interactiveElement = strings.TrimSpace(interactiveElement); interactiveElement != "" {
+			interactiveElements = append(interactiveElements, interactiveElement)
+		}
+	}
+
+	return interactiveElements, nil
+}
+
+// returns the price range of the products which can be bought in the app, for example "€0.89 - €99.99 per item", empty
+// if there are none
+func getInAppProducts(document soup.Root, selectors SelectorProfile, locale Locale) (string, error) {
+	return getMainInformationBlockAdditionalOptionalEntryText(document, selectors, locale, "inAppProducts", additionalInAppProducts)
+}
+
+// returns the name of the company or person offering the app
+func getOfferedBy(document soup.Root, selectors SelectorProfile, locale Locale) (string, error) {
+	return getMainInformationBlockAdditionalEntryText(document, selectors, locale, "offeredBy", additionalOfferedBy)
+}
+
+// returns the website, the email address, the postal address and the link to the privacy policy of the developer
+func getDeveloperContact(document soup.Root, selectors SelectorProfile, locale Locale) (string, string, string, string, error) {
+	website, email, address, privacyPolicy := "", "", "", ""
+
+	developerEntry, developerEntryError := getMainInformationBlockAdditionalEntry(document, selectors, locale, "developerContact", additionalDeveloper)
+	if developerEntryError != nil {
+		return website, email, address, privacyPolicy, developerEntryError
+	}
+	for _, developerLine := range getEntryLines(developerEntry) {
+		developerLink := developerLine.Find(a)
+		if developerLink.Error != nil {
+			if len(developerLine.Children()) == 0 {
+				address = strings.TrimSpace(developerLine.Text())
+			}
+			continue
+		}
+		link := developerLink.GetAttribute(href)
+		linkEntry, _ := locale.translateLabel(developerLink.Text())
+		switch {
+		case link == "":
+		case strings.HasPrefix(link, "mailto:"):
+			email = strings.TrimPrefix(link, "mailto:")
+		case linkEntry == additionalPrivacyPolicy:
+			privacyPolicy = link
+		case website == "":
+			website = link
+		default:
+			privacyPolicy = link
+		}
+	}
+	if website == "" && email == "" && address == "" && privacyPolicy == "" {
+		return website, email, address, privacyPolicy, errors.New("developerContact : entry \"" + additionalDeveloper + "\" of <div class=\"" + selectors.ClassMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" doesn't contain any contact")
+	}
+
+	return website, email, address, privacyPolicy, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// returns the page with the entries of the additional information block in reverse order
+func reverseAdditionalEntries(t *testing.T, page string) string {
+	const entryStart = `<div class="hAyfc">`
+	const entryEnd = `</span></div></span></div>`
+
+	parts := strings.Split(page, entryStart)
+	lastPart := parts[len(parts)-1]
+	lastEntryEnd := strings.Index(lastPart, entryEnd)
+	if len(parts) < 3 || lastEntryEnd < 0 {
+		t.Fatalf("the page doesn't contain an additional information block")
+	}
+	entries := append([]string{}, parts[1:len(parts)-1]...)
+	entries = append(entries, lastPart[:lastEntryEnd+len(entryEnd)])
+	for left, right := 0, len(entries)-1; left < right; left, right = left+1, right-1 {
+		entries[left], entries[right] = entries[right], entries[left]
+	}
+
+	return parts[0] + entryStart + strings.Join(entries, entryStart) + lastPart[lastEntryEnd+len(entryEnd):]
+}
+
+func TestAdditionalInformationByLabel(t *testing.T) {
+	fetcher := newTestFetcher(map[string]string{})
+	defer fetcher.Close()
+
+	for _, fixture := range []struct {
+		name     string
+		language string
+	}{
+		{"ads-iap", "en"},
+		{"paid-de", "de"},
+	} {
+		t.Run(fixture.name, func(t *testing.T) {
+			page := readFixture(t, "app-pages", fixture.name)
+			options := CrawlOptions{Fetcher: fetcher, Language: fixture.language}.withDefaults()
+			want := domStrategy{}.Extract(context.Background(), parseDoc(page), options).AppPage
+			got := domStrategy{}.Extract(context.Background(), parseDoc(reverseAdditionalEntries(t, page)), options).AppPage
+
+			for _, field := range []struct {
+				name      string
+				want, got interface{}
+			}{
+				{fieldLastUpdate, want.LastUpdate, got.LastUpdate},
+				{fieldEstimatedDownloadNumber, want.EstimatedDownloadNumber, got.EstimatedDownloadNumber},
+				{fieldCurrentSoftwareVersion, want.CurrentSoftwareVersion, got.CurrentSoftwareVersion},
+				{fieldRequiresOsVersion, want.RequiresOsVersion, got.RequiresOsVersion},
+				{fieldSize, want.Size, got.Size},
+				{fieldInAppProducts, want.InAppProducts, got.InAppProducts},
+				{fieldOfferedBy, want.OfferedBy, got.OfferedBy},
+				{fieldDeveloperEmail, want.DeveloperEmail, got.DeveloperEmail},
+				{fieldContentRatingDescriptors, want.ContentRatingDescriptors, got.ContentRatingDescriptors},
+			} {
+				if !reflect.DeepEqual(field.want, field.got) {
+					t.Errorf("%s should not depend on the order of the entries, got %v instead of %v", field.name, field.got, field.want)
+				}
+			}
+			if want.LastUpdate == nil || want.Size == "" || want.OfferedBy == "" || want.DeveloperEmail == "" {
+				t.Errorf("entries should be extracted, got %+v", want)
+			}
+		})
+	}
+}
+
+func TestDeveloperContact(t *testing.T) {
+	document := parseDoc(readFixture(t, "app-pages", "ads-iap"))
+	website, email, address, privacyPolicy, contactError := getDeveloperContact(document, defaultSelectorProfile, getLocale("en"))
+	if contactError != nil {
+		t.Fatalf("developer contact should be extracted, got %v", contactError)
+	}
+	if website != "http://candycrushsaga.com" || email != "candycrush.techsupport@king.com" || privacyPolicy != "https://king.com/privacyPolicy" {
+		t.Errorf("unexpected developer contact %q, %q, %q", website, email, privacyPolicy)
+	}
+	if address != "Sveavägen 44\n111 34 Stockholm" {
+		t.Errorf("address should keep its line breaks, got %q", address)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+const (
+	defaultBatchConcurrency = 4
+	maxBatchSize            = 500
+
+	// errors
+	errorBatchCrawlFailed = "crawling the app page failed"
+)
+
+// CrawlBatch crawls the app pages of all packages with at most "concurrency" requests at the same time,
+// the result contains one app page per package name in the same order, crawls still running when the context is done
+// are canceled
+func CrawlBatch(ctx context.Context, packageNames []string, options CrawlOptions, concurrency int) []AppPage {
+	return crawlConcurrently(ctx, packageNames, options.withDefaults(), concurrency, CrawlContext)
+}
+
+// crawls the app pages of all packages with the crawl function, at most "concurrency" at the same time. The result
+// contains one app page per package name in the same order
+func crawlConcurrently(ctx context.Context, packageNames []string, options CrawlOptions, concurrency int, crawl func(ctx context.Context, packageName string, options CrawlOptions) (AppPage, error)) []AppPage {
+	appPages := make([]AppPage, len(packageNames))
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	positions := make(chan int)
+	var waitGroup sync.WaitGroup
+	for worker := 0; worker < concurrency && worker < len(packageNames); worker++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for position := range positions {
+				appPages[position] = crawlBatchEntry(ctx, packageNames[position], options, crawl)
+			}
+		}()
+	}
+	for position := range packageNames {
+		positions <- position
+	}
+	close(positions)
+	waitGroup.Wait()
+
+	return appPages
+}
+
+// crawls a single app page of a batch, failures are reported in the app page instead of aborting the batch
+func crawlBatchEntry(ctx context.Context, packageName string, options CrawlOptions, crawl func(ctx context.Context, packageName string, options CrawlOptions) (AppPage, error)) (appPage AppPage) {
+	defer func() {
+		if r := recover(); r != nil {
+			appPage = AppPage{PackageName: packageName, Language: options.Language, Country: options.Country}
+			appPage.Errors = append(appPage.Errors, errorBatchCrawlFailed+" : "+fmt.Sprint(r))
+		}
+	}()
+
+	appPage, crawlError := crawl(ctx, packageName, options)
+	if crawlError != nil {
+		appPage.PackageName = packageName
+		appPage.Language = options.Language
+		appPage.Country = options.Country
+		appPage.Errors = append(appPage.Errors, crawlError.Error())
+	}
+
+	return appPage
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingFetcher records how many requests are running at the same time
+type countingFetcher struct {
+	fetcher     Fetcher
+	mutex       sync.Mutex
+	running     int
+	maxRunning  int
+	totalCalled int
+}
+
+func (fetcher *countingFetcher) Fetch(ctx context.Context, url string) (FetchResponse, error) {
+	fetcher.mutex.Lock()
+	fetcher.running++
+	fetcher.totalCalled++
+	if fetcher.running > fetcher.maxRunning {
+		fetcher.maxRunning = fetcher.running
+	}
+	fetcher.mutex.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+	response, err := fetcher.fetcher.Fetch(ctx, url)
+
+	fetcher.mutex.Lock()
+	fetcher.running--
+	fetcher.mutex.Unlock()
+
+	return response, err
+}
+
+func TestCrawlBatch(t *testing.T) {
+	pages := newTestFetcher(loadAppPageFixtures())
+	defer pages.Close()
+	fetcher := &countingFetcher{fetcher: pages}
+
+	packageNames := []string{"com.whatsapp", "com.does.not.exists.122", "com.king.candycrushsaga", "com.tinyapps.notes", "com.ustwo.monumentvalley"}
+	appPages := CrawlBatch(context.Background(), packageNames, CrawlOptions{Fetcher: fetcher}, 2)
+	if len(appPages) != len(packageNames) {
+		t.Fatalf("there should be %d app pages, got %d", len(packageNames), len(appPages))
+	}
+	for position, appPage := range appPages {
+		if appPage.PackageName != packageNames[position] {
+			t.Errorf("app page %d should belong to %s, got %s", position, packageNames[position], appPage.PackageName)
+		}
+	}
+	if appPages[0].Name != "WhatsApp Messenger" {
+		t.Errorf("first app page should be crawled, got name %q", appPages[0].Name)
+	}
+	if len(appPages[1].Errors) != 1 || !strings.Contains(appPages[1].Errors[0], ErrNotFound.Error()) {
+		t.Errorf("missing app should report an error, got %v", appPages[1].Errors)
+	}
+	if fetcher.maxRunning > 2 {
+		t.Errorf("there should be at most 2 requests at the same time, got %d", fetcher.maxRunning)
+	}
+}
+
+func TestCrawlConcurrentlyFailures(t *testing.T) {
+	crawl := func(ctx context.Context, packageName string, options CrawlOptions) (AppPage, error) {
+		if packageName == "com.panics" {
+			panic("unexpected page")
+		}
+		return AppPage{}, ErrNotFound
+	}
+
+	appPages := crawlConcurrently(context.Background(), []string{"com.panics", "com.missing"}, CrawlOptions{Language: "de", Country: "AT"}, 2, crawl)
+	for position, packageName := range []string{"com.panics", "com.missing"} {
+		appPage := appPages[position]
+		if appPage.PackageName != packageName || appPage.Language != "de" || appPage.Country != "AT" || len(appPage.Errors) != 1 {
+			t.Errorf("failed crawl of %s should be reported in its app page, got %+v", packageName, appPage)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// hosts and paths Google redirects to instead of showing the app page
+	hostConsent = "consent.google.com"
+	pathSorry   = "/sorry/"
+
+	// reasons why the request was blocked
+	blockedReasonTooManyRequests = "too many requests"
+	blockedReasonUnusualTraffic  = "unusual traffic page"
+	blockedReasonConsent         = "consent interstitial"
+)
+
+// markers in the html of the page which is shown instead of the app page, they don't depend on the language
+var (
+	blockedMarkersUnusualTraffic = []string{"id=\"captcha-form\"", "class=\"g-recaptcha\"", "google.com/sorry/", "unusual traffic from your computer network"}
+	blockedMarkersConsent        = []string{"action=\"https://consent.google.com", "consent.google.com/save", "consent.google.com/s?"}
+)
+
+// returns the reason if the Google Play Store didn't answer with the app page but with a captcha or consent page,
+// otherwise the returned reason is empty
+func getBlockedReason(response FetchResponse, selectors SelectorProfile) string {
+	if response.StatusCode == http.StatusTooManyRequests {
+		return blockedReasonTooManyRequests
+	}
+
+	responseURL, parseError := url.Parse(response.URL)
+	if parseError == nil {
+		if responseURL.Host == hostConsent {
+			return blockedReasonConsent
+		}
+		if strings.HasPrefix(responseURL.Path, pathSorry) {
+			return blockedReasonUnusualTraffic
+		}
+	}
+
+	// the app page itself doesn't contain any form, so the markers are only checked for pages without the app content
+	if !strings.Contains(response.Body, "class=\""+selectors.ClassAppPage+"\"") {
+		for _, marker := range blockedMarkersUnusualTraffic {
+			if strings.Contains(response.Body, marker) {
+				return blockedReasonUnusualTraffic
+			}
+		}
+		for _, marker := range blockedMarkersConsent {
+			if strings.Contains(response.Body, marker) {
+				return blockedReasonConsent
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetBlockedReason(t *testing.T) {
+	for _, testCase := range []struct {
+		name     string
+		response FetchResponse
+		reason   string
+	}{
+		{"app page", FetchResponse{StatusCode: http.StatusOK, URL: "https://play.google.com/store/apps/details?id=com.whatsapp", Body: readFixture(t, "app-pages", "free")}, ""},
+		{"removed app", FetchResponse{StatusCode: http.StatusNotFound, URL: "https://play.google.com/store/apps/details?id=com.does.not.exists.122", Body: readFixture(t, "app-pages", "removed")}, ""},
+		{"too many requests", FetchResponse{StatusCode: http.StatusTooManyRequests}, blockedReasonTooManyRequests},
+		{"sorry redirect", FetchResponse{StatusCode: http.StatusOK, URL: "https://www.google.com/sorry/index?continue=https://play.google.com/store/apps/details"}, blockedReasonUnusualTraffic},
+		{"sorry page", FetchResponse{StatusCode: http.StatusServiceUnavailable, Body: readFixture(t, "blocked", "sorry")}, blockedReasonUnusualTraffic},
+		{"consent redirect", FetchResponse{StatusCode: http.StatusOK, URL: "https://consent.google.com/ml?continue=https://play.google.com/store/apps/details"}, blockedReasonConsent},
+		{"consent page", FetchResponse{StatusCode: http.StatusOK, Body: readFixture(t, "blocked", "consent")}, blockedReasonConsent},
+	} {
+		if reason := getBlockedReason(testCase.response, defaultSelectorProfile); reason != testCase.reason {
+			t.Errorf("%s : reason should be %q, got %q", testCase.name, testCase.reason, reason)
+		}
+	}
+}
+
+func TestCrawlBlocked(t *testing.T) {
+	sorryPage := readFixture(t, "blocked", "sorry")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sorry/index" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(sorryPage))
+			return
+		}
+		http.Redirect(w, r, "/sorry/index?continue="+r.URL.RequestURI(), http.StatusFound)
+	}))
+	defer server.Close()
+
+	fetcher := &testFetcher{server: server, fetcher: HTTPFetcher{Client: server.Client()}}
+	appPage, err := Crawl("com.whatsapp", CrawlOptions{Fetcher: fetcher})
+	if !errors.Is(err, ErrBlocked) {
+		t.Errorf("error should be ErrBlocked, got %v", err)
+	}
+	if !appPage.Blocked || appPage.PackageName != "com.whatsapp" {
+		t.Errorf("app page should be marked as blocked, got %+v", appPage)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCacheTTLSeconds = 3600
+	defaultCacheSize       = 1000
+)
+
+// AppPageCache keeps crawled app pages for a limited time, the least recently used pages are dropped from memory once
+// the capacity is reached, if a directory is set the pages are also kept on disk and survive restarts
+type AppPageCache struct {
+	capacity  int
+	ttl       time.Duration
+	directory string
+	now       func() time.Time
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	recent  *list.List
+}
+
+// cached app page together with the time it was crawled
+type appPageCacheEntry struct {
+	Key     string    `json:"key"`
+	Stored  time.Time `json:"stored"`
+	AppPage AppPage   `json:"app_page"`
+}
+
+// NewAppPageCache returns an empty cache, the on-disk tier is only used if the directory is not empty
+func NewAppPageCache(capacity int, ttl time.Duration, directory string) (*AppPageCache, error) {
+	if directory != "" {
+		directoryError := os.MkdirAll(directory, 0755)
+		if directoryError != nil {
+			return nil, directoryError
+		}
+	}
+
+	return &AppPageCache{
+		capacity:  capacity,
+		ttl:       ttl,
+		directory: directory,
+		now:       time.Now,
+		entries:   map[string]*list.Element{},
+		recent:    list.New(),
+	}, nil
+}
+
+// returns the key of an app page in a storefront
+func appPageCacheKey(packageName string, options CrawlOptions) string {
+	return packageName + "|" + options.Language + "|" + options.Country
+}
+
+// Get returns the cached app page and its age, the page is only returned if it is younger than the ttl
+func (cache *AppPageCache) Get(key string) (AppPage, time.Duration, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if element, exists := cache.entries[key]; exists {
+		entry := element.Value.(appPageCacheEntry)
+		age := cache.now().Sub(entry.Stored)
+		if age < cache.ttl {
+			cache.recent.MoveToFront(element)
+			return entry.AppPage, age, true
+		}
+		cache.recent.Remove(element)
+		delete(cache.entries, key)
+	}
+
+	entry, found := cache.readEntry(key)
+	if found {
+		age := cache.now().Sub(entry.Stored)
+		if age < cache.ttl {
+			cache.add(entry)
+			return entry.AppPage, age, true
+		}
+	}
+
+	return AppPage{}, 0, false
+}
+
+// Set stores the app page as crawled right now
+func (cache *AppPageCache) Set(key string, appPage AppPage) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry := appPageCacheEntry{Key: key, Stored: cache.now(), AppPage: appPage}
+	cache.add(entry)
+	cache.writeEntry(entry)
+}
+
+// TTL returns how long app pages are kept
+func (cache *AppPageCache) TTL() time.Duration {
+	return cache.ttl
+}
+
+// adds the entry to the memory tier and drops the least recently used entries exceeding the capacity
+func (cache *AppPageCache) add(entry appPageCacheEntry) {
+	if element, exists := cache.entries[entry.Key]; exists {
+		cache.recent.Remove(element)
+	}
+	cache.entries[entry.Key] = cache.recent.PushFront(entry)
+	for cache.recent.Len() > cache.capacity {
+		oldest := cache.recent.Back()
+		cache.recent.Remove(oldest)
+		delete(cache.entries, oldest.Value.(appPageCacheEntry).Key)
+	}
+}
+
+// returns the file of the entry in the on-disk tier
+func (cache *AppPageCache) entryPath(key string) string {
+	hash := sha1.Sum([]byte(key))
+	return filepath.Join(cache.directory, hex.EncodeToString(hash[:])+".json")
+}
+
+// reads the entry from the on-disk tier
+func (cache *AppPageCache) readEntry(key string) (appPageCacheEntry, bool) {
+	var entry appPageCacheEntry
+	if cache.directory == "" {
+		return entry, false
+	}
+
+	entryJSON, readError := ioutil.ReadFile(cache.entryPath(key))
+	if readError != nil {
+		return entry, false
+	}
+	decodeError := json.Unmarshal(entryJSON, &entry)
+
+	return entry, decodeError == nil && entry.Key == key
+}
+
+// writes the entry to the on-disk tier, failures only cost a crawl later on and are therefore just logged
+func (cache *AppPageCache) writeEntry(entry appPageCacheEntry) {
+	if cache.directory == "" {
+		return
+	}
+
+	entryJSON, encodeError := json.Marshal(entry)
+	if encodeError == nil {
+		encodeError = ioutil.WriteFile(cache.entryPath(entry.Key), entryJSON, 0644)
+	}
+	if encodeError != nil {
+		fmt.Println("could not write", entry.Key, "to the cache directory :", encodeError)
+	}
+}
+
+// CrawlCached returns the cached app page if there is one, otherwise the page is crawled and cached on success,
+// fresh skips the lookup but still caches the result, the returned duration is the age of the app page. App pages
+// crawled with a media directory are not cached, their media files only belong to the request which downloaded them
+func CrawlCached(ctx context.Context, cache *AppPageCache, packageName string, options CrawlOptions, fresh bool) (AppPage, time.Duration, error) {
+	if cache == nil {
+		appPage, crawlError := CrawlContext(ctx, packageName, options)
+		return appPage, 0, crawlError
+	}
+
+	options = options.withDefaults()
+	key := appPageCacheKey(packageName, options)
+	if !fresh {
+		if appPage, age, found := cache.Get(key); found {
+			return appPage, age, nil
+		}
+	}
+
+	appPage, crawlError := CrawlContext(ctx, packageName, options)
+	if crawlError == nil && options.MediaDirectory == "" {
+		cache.Set(key, appPage)
+	}
+
+	return appPage, 0, crawlError
+}
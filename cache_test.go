@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAppPageCache(t *testing.T) {
+	cache, _ := NewAppPageCache(2, time.Minute, "")
+	now := time.Date(2019, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	cache.Set("a", AppPage{Name: "a"})
+	cache.Set("b", AppPage{Name: "b"})
+	// "a" is used again, so "b" is the least recently used page when "c" is added
+	cache.Get("a")
+	cache.Set("c", AppPage{Name: "c"})
+	if _, _, found := cache.Get("b"); found {
+		t.Errorf("least recently used page should have been dropped")
+	}
+
+	now = now.Add(30 * time.Second)
+	appPage, age, found := cache.Get("a")
+	if !found || appPage.Name != "a" || age != 30*time.Second {
+		t.Errorf("page should be cached with age 30s, got %v %v %v", found, appPage.Name, age)
+	}
+
+	now = now.Add(time.Minute)
+	if _, _, found := cache.Get("a"); found {
+		t.Errorf("page older than the ttl should not be returned")
+	}
+}
+
+func TestAppPageCacheOnDisk(t *testing.T) {
+	directory, _ := ioutil.TempDir("", "app-page-cache")
+	defer os.RemoveAll(directory)
+
+	cache, err := NewAppPageCache(10, time.Minute, directory)
+	if err != nil {
+		t.Fatalf("cache should be created : %v", err)
+	}
+	cache.Set("com.whatsapp|en|", AppPage{Name: "WhatsApp Messenger"})
+
+	// a new cache, e.g. after a restart, reads the page from disk
+	restarted, _ := NewAppPageCache(10, time.Minute, directory)
+	appPage, _, found := restarted.Get("com.whatsapp|en|")
+	if !found || appPage.Name != "WhatsApp Messenger" {
+		t.Errorf("page should be read from disk, got %v %q", found, appPage.Name)
+	}
+	if _, _, found := restarted.Get("com.whatsapp|de|"); found {
+		t.Errorf("page of another language should not be found")
+	}
+}
+
+func TestGetAppPageCached(t *testing.T) {
+	cache, _ := NewAppPageCache(10, time.Hour, "")
+	appPageCache = cache
+	defer func() { appPageCache = nil }()
+
+	endpoint := "/hitec/crawl/app-page/google-play/com.ustwo.monumentvalley"
+	requestCount := func() int { return len(routerFetcher.Requested()) }
+
+	before := requestCount()
+	rr := executeRequest(buildRequest("GET", endpoint, nil, t))
+	if rr.Code != http.StatusOK || rr.Header().Get("Age") != "0" || rr.Header().Get("Cache-Control") != "max-age=3600" {
+		t.Fatalf("first request should crawl the page, got status %d and headers %v", rr.Code, rr.Header())
+	}
+	crawled := requestCount()
+	if crawled == before {
+		t.Errorf("first request should fetch the page")
+	}
+
+	rr = executeRequest(buildRequest("GET", endpoint, nil, t))
+	if rr.Code != http.StatusOK || requestCount() != crawled {
+		t.Errorf("second request should be served from the cache")
+	}
+
+	rr = executeRequest(buildRequest("GET", endpoint+"?fresh=true", nil, t))
+	if rr.Code != http.StatusOK || requestCount() == crawled {
+		t.Errorf("fresh request should bypass the cache")
+	}
+
+	crawled = requestCount()
+	executeRequest(buildRequest("GET", endpoint+"?hl=de", nil, t))
+	if requestCount() == crawled {
+		t.Errorf("request in another language should not be served from the cache")
+	}
+}
+
+func TestCrawlCachedSkipsMediaDownloads(t *testing.T) {
+	pages := newTestFetcher(loadAppPageFixtures())
+	defer pages.Close()
+	directory, _ := ioutil.TempDir("", "media")
+	defer os.RemoveAll(directory)
+	cache, _ := NewAppPageCache(10, time.Hour, "")
+
+	options := CrawlOptions{Fetcher: mediaFetcher{fetcher: pages, images: map[string]string{}}, MediaDirectory: directory}
+	if _, _, err := CrawlCached(context.Background(), cache, "com.whatsapp", options, true); err != nil {
+		t.Fatalf("app page should be crawled, got %v", err)
+	}
+	if _, _, found := cache.Get(appPageCacheKey("com.whatsapp", options.withDefaults())); found {
+		t.Errorf("app page crawled with a media directory should not be cached")
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slowFetcher delays the responses for urls containing the pattern until the context is done
+type slowFetcher struct {
+	fetcher Fetcher
+	pattern string
+}
+
+func (fetcher slowFetcher) Fetch(ctx context.Context, url string) (FetchResponse, error) {
+	if strings.Contains(url, fetcher.pattern) {
+		<-ctx.Done()
+		return FetchResponse{}, ctx.Err()
+	}
+
+	return fetcher.fetcher.Fetch(ctx, url)
+}
+
+func TestCrawlContextTimeout(t *testing.T) {
+	pages := newTestFetcher(loadAppPageFixtures())
+	defer pages.Close()
+
+	// the similar apps page doesn't answer in time, so only the app page itself is crawled
+	options := CrawlOptions{Fetcher: slowFetcher{fetcher: pages, pattern: "/cluster"}, Timeout: 50 * time.Millisecond}
+	appPage, err := CrawlContext(context.Background(), "com.whatsapp", options)
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("crawl should time out, got %v", err)
+	}
+	if appPage.Name != "WhatsApp Messenger" {
+		t.Errorf("partially crawled app page should be returned, got name %q", appPage.Name)
+	}
+	if response := getErrorResponse(err); response.Status != http.StatusGatewayTimeout {
+		t.Errorf("timeout should be reported with status 504, got %d", response.Status)
+	}
+
+	// the app page itself doesn't answer in time
+	options.Fetcher = slowFetcher{fetcher: pages, pattern: "/details"}
+	appPage, err = CrawlContext(context.Background(), "com.whatsapp", options)
+	if !errors.Is(err, ErrTimeout) || appPage.Name != "" {
+		t.Errorf("crawl should time out without app page, got %v", err)
+	}
+
+	// the client went away
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := CrawlContext(ctx, "com.whatsapp", CrawlOptions{Fetcher: pages}); !errors.Is(err, ErrCanceled) {
+		t.Errorf("crawl should be canceled, got %v", err)
+	}
+}
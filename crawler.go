@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -11,60 +13,39 @@ import (
 	"net/http"
 
 	"github.com/OlegSchmidt/soup"
-	"github.com/jehiah/go-strftime"
 )
 
 const (
 	baseURL        = "https://play.google.com"
 	baseURLAppPage = baseURL + "/store/apps/details?id="
-	lang           = "&hl=en"
+
+	// maximum duration of a crawl requested via the API
+	defaultCrawlTimeoutSeconds = 30
 
 	// common html nodes
 	a    = "a"
 	h1   = "h1"
 	h2   = "h2"
+	h3   = "h3"
 	div  = "div"
 	span = "span"
 	meta = "meta"
 	img  = "img"
 
 	// common html attributes
-	class    = "class"
-	style    = "style"
-	href     = "href"
-	itemprop = "itemprop"
-	alt      = "alt"
-	content  = "content"
+	class     = "class"
+	style     = "style"
+	href      = "href"
+	itemprop  = "itemprop"
+	alt       = "alt"
+	content   = "content"
+	title     = "title"
+	ariaLabel = "aria-label"
 
 	// common style attribute values
 	styleWidth = "width"
 
-	// CSS classes for finding the right elements
-	classMainInformationAppContainer        = "oQ6oV"
-	classMainInformationSimilarContainer    = "Ktdaqe"
-	classMainInformationApp                 = "rlnrKc"
-	classMainInformationSimilar             = "ZmHEEd"
-	classMainInformationHeadline            = "Rm6Gwb"
-	classMainInformationAdditionalContainer = "IxB2fe"
-	classAppPage                            = "LXrl4c"
-	classAppCategoryUsk                     = "ZVWMWc"
-	classAppRating                          = "BHMmbe"
-	classAppStarsCount                      = "EymY4b"
-	classAppCountPerRating                  = "VEF2C"
-	classAppContainsAds                     = "bSIuKf"
-	classAppInAppPurchases                  = "bSIuKf"
-
-	// itemprop values
-	itempropAppName         = "name"
-	itempropAppCategory     = "genre"
-	itempropAppPrice        = "price"
-	itempropAppDescription  = "description"
-	itempropAppTopDeveloper = "editorsChoiceBadgeUrl"
-
 	// element values as strings
-	valueContainsAds                   = "Contains Ads"
-	valueInAppPurchases                = "Offers in-app purchases"
-	valueRequiresOsVersion             = "Varies with device"
 	valueCurrentSoftwareVersionDefault = "unknown"
 
 	// block types
@@ -73,159 +54,337 @@ const (
 	blockTypeWhatsNew   = "whats new"
 	blockTypeAdditional = "additional"
 
+	// path of the data safety section of an app
+	pathDataSafety = "/store/apps/datasafety"
+	// rpc returning the permissions of an app
+	permissionRPCID = "xdSrCf"
+
+	// parts of the data safety section, independent of the language of the page
+	dataSafetyShared             = "data shared"
+	dataSafetyCollected          = "data collected"
+	dataSafetySecurityPractices  = "security practices"
+	dataSafetyEncryptedInTransit = "encrypted in transit"
+	dataSafetyDeletionRequest    = "deletion request"
+	dataSafetyOptional           = "optional"
+
+	// badges of the main information block and the required android version of apps supporting every version,
+	// independent of the language of the page
+	badgeContainsAds      = "contains ads"
+	badgeInAppPurchases   = "in-app purchases"
+	valueVariesWithDevice = "varies with device"
+
 	// errors
-	errorPageNotFound = "Page content not found, please update the CSS class in the constant \"classAppPage\""
+	errorPageNotFound = "Page content not found, please update \"class_app_page\" in the selector profile"
 )
 
-// Crawl the information available on a app page
-func Crawl(packageName string) AppPage {
+// CrawlOptions model, configures how and in which storefront an app page is crawled
+type CrawlOptions struct {
+	Fetcher    Fetcher
+	Language   string
+	Country    string
+	Selectors  SelectorProfile
+	Strategies []ExtractionStrategy
+	// successfully crawled app pages are saved in the store, nil doesn't save them
+	Store Store
+	// maximum duration of the whole crawl including all sub-requests, zero doesn't limit it
+	Timeout time.Duration
+	// the images of successfully crawled app pages are downloaded into the directory, empty doesn't download them
+	MediaDirectory string
+}
+
+// fills the options which were not set with their defaults
+func (options CrawlOptions) withDefaults() CrawlOptions {
+	if options.Fetcher == nil {
+		options.Fetcher = NewHTTPFetcher()
+	}
+	if options.Language == "" {
+		options.Language = defaultLanguage
+	}
+	if options.Selectors.Version == "" {
+		options.Selectors = getSelectorProfile()
+	}
+	if len(options.Strategies) == 0 {
+		options.Strategies = defaultStrategies
+	}
+
+	return options
+}
+
+// returns the url of the app page in the language and country of the options
+func (options CrawlOptions) appPageURL(packageName string) string {
+	pageURL := baseURLAppPage + url.QueryEscape(packageName) + "&hl=" + url.QueryEscape(options.Language)
+	if options.Country != "" {
+		pageURL += "&gl=" + url.QueryEscape(options.Country)
+	}
+
+	return pageURL
+}
+
+// Crawl the information available on a app page, see CrawlContext
+func Crawl(packageName string, options CrawlOptions) (AppPage, error) {
+	return CrawlContext(context.Background(), packageName, options)
+}
+
+// CrawlContext crawls the information available on a app page, all requests are canceled together with the context or
+// when the timeout of the options passes, the returned error wraps ErrNotFound, ErrBlocked, ErrUpstreamUnavailable,
+// ErrLayoutChanged or ErrTimeout, on ErrTimeout the app page contains the fields crawled so far
+func CrawlContext(ctx context.Context, packageName string, options CrawlOptions) (AppPage, error) {
 	var appPage AppPage
+	options = options.withDefaults()
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	pageURL := options.appPageURL(packageName)
+	document, response, retrieveError := retrieveDoc(ctx, options.Fetcher, pageURL)
+	if retrieveError != nil {
+		return appPage, &CrawlError{Err: getContextError(ctx, ErrUpstreamUnavailable), PackageName: packageName, URL: pageURL, Detail: retrieveError.Error()}
+	}
+	if blockedReason := getBlockedReason(response, options.Selectors); blockedReason != "" {
+		appPage = AppPage{PackageName: packageName, DateCrawled: getCrawlTime(), Language: options.Language, Country: options.Country, Os: getOs(), SelectorProfileVersion: options.Selectors.Version, Blocked: true}
+		return appPage, &CrawlError{Err: ErrBlocked, PackageName: packageName, URL: pageURL, StatusCode: response.StatusCode, RetryAfter: response.Header.Get("Retry-After"), Detail: blockedReason}
+	}
+	if response.StatusCode != http.StatusOK {
+		return appPage, &CrawlError{Err: errorFromStatusCode(response.StatusCode), PackageName: packageName, URL: pageURL, StatusCode: response.StatusCode, RetryAfter: response.Header.Get("Retry-After")}
+	}
 
-	document, httpStatus := retrieveDoc(packageName)
-	if httpStatus == http.StatusOK {
-		appPage = crawlAppPage(document, packageName)
-		if appPage.Description == "" && appPage.Name == "" && appPage.DeveloperName == "" {
-			// probably captcha
+	appPage = crawlAppPage(ctx, document, packageName, options)
+	if ctx.Err() != nil {
+		// the sub-requests were canceled, so the app page is only partially filled
+		return appPage, &CrawlError{Err: getContextError(ctx, ErrTimeout), PackageName: packageName, URL: pageURL, Detail: ctx.Err().Error()}
+	}
+	if appPage.Description == "" && appPage.Name == "" && appPage.DeveloperName == "" {
+		// captcha and consent pages were detected before, so none of the elements could be found anymore
+		crawlError := &CrawlError{Err: ErrLayoutChanged, PackageName: packageName, URL: pageURL}
+		if len(appPage.Errors) > 0 {
+			crawlError.Detail = appPage.Errors[0]
+		}
+		return appPage, crawlError
+	}
+	if options.MediaDirectory != "" {
+		var mediaErrors []string
+		appPage.MediaFiles, mediaErrors = downloadMedia(ctx, appPage, options)
+		appPage.Errors = append(appPage.Errors, mediaErrors...)
+	}
+	if options.Store != nil {
+		// the crawl itself succeeded, so a failing database doesn't fail it
+		storeError := options.Store.SaveAppPage(appPage)
+		if storeError != nil {
+			fmt.Println("\tcould not store", packageName, "because of the following error:")
+			fmt.Println(storeError)
 		}
 	}
 
-	return appPage
+	return appPage, nil
+}
+
+// returns ErrTimeout if the deadline of the context passed, ErrCanceled if it was canceled and the fallback otherwise
+func getContextError(ctx context.Context, fallback error) error {
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return ErrTimeout
+	case context.Canceled:
+		return ErrCanceled
+	}
+
+	return fallback
 }
 
-// parses the website and returns the DOM struct
-func retrieveDoc(packageName string) (soup.Root, int) {
-	url := baseURLAppPage + packageName + lang
+// parses the website and returns the DOM struct together with the response
+func retrieveDoc(ctx context.Context, fetcher Fetcher, url string) (soup.Root, FetchResponse, error) {
 	var document soup.Root
-	httpStatus := http.StatusOK
-	// retrieving the html page
-	response, soupError := soup.Get(url)
-	if soupError != nil {
-		fmt.Println("\tcould not reach", url, "because of the following error:")
-		fmt.Println(soupError)
-		httpStatus = http.StatusBadRequest
-	} else {
-		// pre-process html
-		response = strings.Replace(response, "<br>", "\n", -1)
-		response = strings.Replace(response, "<b>", "", -1)
-		response = strings.Replace(response, "</b>", "", -1)
-		document = soup.HTMLParse(response)
+	// retrieving the html page, the fetcher is responsible for retrying transient errors
+	response, fetchError := fetcher.Fetch(ctx, url)
+	if fetchError == nil {
+		document = parseDoc(response.Body)
 	}
 
-	return document, httpStatus
+	return document, response, fetchError
 }
 
-// crawls the page and fills the struct with values
-func crawlAppPage(document soup.Root, packageName string) AppPage {
-	var lastError error
+// pre-processes the html and returns the DOM struct
+func parseDoc(html string) soup.Root {
+	html = strings.Replace(html, "<br>", "\n", -1)
+	html = strings.Replace(html, "<b>", "", -1)
+	html = strings.Replace(html, "</b>", "", -1)
+
+	return soup.HTMLParse(html)
+}
+
+// crawls the page and fills the struct with values, each field is taken from the first strategy able to extract it
+func crawlAppPage(ctx context.Context, document soup.Root, packageName string, options CrawlOptions) AppPage {
 	appPage := AppPage{}
-	appPage.DateCrawled = getCurrentDate()
+	appPage.DateCrawled = getCrawlTime()
 	appPage.PackageName = packageName
+	appPage.Language = options.Language
+	appPage.Country = options.Country
+	appPage.SelectorProfileVersion = options.Selectors.Version
 	appPage.Os = getOs()
 	if document.Error != nil {
 		appPage.Errors = append(appPage.Errors, document.Error.Error())
 	} else {
-		appPageDocument, appPageDocumentError := getPageDocument(document)
-		if appPageDocumentError == nil {
-			appPage.Name, lastError = getAppName(appPageDocument)
-			if lastError != nil {
-				appPage.Errors = append(appPage.Errors, lastError.Error())
+		var extractions []Extraction
+		for _, strategy := range options.Strategies {
+			if ctx.Err() != nil {
+				break
 			}
+			extractions = append(extractions, strategy.Extract(ctx, document, options))
+		}
+		if ctx.Err() == nil {
+			extractions = append(extractions, extractDetails(ctx, document, packageName, options))
+		}
+		appPage = mergeExtractions(appPage, extractions)
+	}
 
-			appPage.Category, lastError = getCategory(appPageDocument)
-			if lastError != nil {
-				appPage.Errors = append(appPage.Errors, lastError.Error())
-			}
+	return appPage
+}
 
-			appPage.USK, lastError = getUsk(appPageDocument)
-			if lastError != nil {
-				appPage.Errors = append(appPage.Errors, lastError.Error())
-			}
+// domStrategy extracts the fields from the html elements of the app page
+type domStrategy struct{}
 
-			appPage.Price, appPage.PriceValue, appPage.PriceCurrency, lastError = getPrice(appPageDocument)
-			if lastError != nil {
-				appPage.Errors = append(appPage.Errors, lastError.Error())
-			}
+func (strategy domStrategy) Name() string {
+	return strategyNameDOM
+}
 
-			appPage.Description, lastError = getDescription(appPageDocument)
-			if lastError != nil {
-				appPage.Errors = append(appPage.Errors, lastError.Error())
-			}
+func (strategy domStrategy) Extract(ctx context.Context, document soup.Root, options CrawlOptions) Extraction {
+	var lastError error
+	extraction := newExtraction(strategy.Name())
+	locale := getLocale(options.Language)
+	selectors := options.Selectors
+	appPage := &extraction.AppPage
+
+	appPageDocument, appPageDocumentError := getPageDocument(document, selectors)
+	if appPageDocumentError != nil {
+		extraction.PageErrors = append(extraction.PageErrors, appPageDocumentError.Error())
+		return extraction
+	}
 
-			appPage.WhatsNew, lastError = getWhatsNew(appPageDocument)
-			if lastError != nil {
-				appPage.Errors = append(appPage.Errors, lastError.Error())
-			}
+	appPage.Name, lastError = getAppName(appPageDocument, selectors)
+	extraction.track(lastError, fieldName)
 
-			appPage.Rating, lastError = getRating(appPageDocument)
-			if lastError != nil {
-				appPage.Errors = append(appPage.Errors, lastError.Error())
-			}
+	appPage.Category, lastError = getCategory(appPageDocument, selectors)
+	extraction.track(lastError, fieldCategory)
 
-			appPage.StarsCount, lastError = getStarsCount(appPageDocument)
-			if lastError != nil {
-				appPage.Errors = append(appPage.Errors, lastError.Error())
-			}
+	appPage.USK, lastError = getUsk(appPageDocument, selectors)
+	extraction.track(lastError, fieldUsk)
 
-			appPage.CountPerRating, lastError = getCountPerRating(appPageDocument)
-			if lastError != nil {
-				appPage.Errors = append(appPage.Errors, lastError.Error())
-			}
+	appPage.Price, appPage.PriceValue, appPage.PriceCurrency, lastError = getPrice(appPageDocument, selectors, locale)
+	extraction.track(lastError, fieldPrice, fieldPriceValue, fieldPriceCurrency)
 
-			appPage.EstimatedDownloadNumber, lastError = getEstimatedDownloadNumber(appPageDocument)
-			if lastError != nil {
-				appPage.Errors = append(appPage.Errors, lastError.Error())
-			}
+	appPage.Description, lastError = getDescription(appPageDocument, selectors)
+	extraction.track(lastError, fieldDescription)
 
-			appPage.DeveloperName, lastError = getDeveloperName(appPageDocument)
-			if lastError != nil {
-				appPage.Errors = append(appPage.Errors, lastError.Error())
-			}
+	appPage.WhatsNew, lastError = getWhatsNew(appPageDocument, selectors)
+	extraction.track(lastError, fieldWhatsNew)
 
-			appPage.TopDeveloper, lastError = getTopDeveloper(appPageDocument)
-			if lastError != nil {
-				appPage.Errors = append(appPage.Errors, lastError.Error())
-			}
+	appPage.Rating, lastError = getRating(appPageDocument, selectors, locale)
+	extraction.track(lastError, fieldRating)
 
-			appPage.ContainsAds, lastError = getContainsAds(appPageDocument)
-			if lastError != nil {
-				appPage.Errors = append(appPage.Errors, lastError.Error())
-			}
+	appPage.StarsCount, lastError = getStarsCount(appPageDocument, selectors)
+	extraction.track(lastError, fieldStarsCount)
 
-			appPage.InAppPurchases, lastError = getInAppPurchases(appPageDocument)
-			if lastError != nil {
-				appPage.Errors = append(appPage.Errors, lastError.Error())
-			}
+	appPage.CountPerRating, lastError = getCountPerRating(appPageDocument, selectors, locale)
+	extraction.track(lastError, fieldCountPerRating)
+	if lastError == nil {
+		appPage.PercentPerRating = getPercentPerRating(appPage.CountPerRating)
+	} else {
+		appPage.PercentPerRating, lastError = getPercentPerRatingFromWidths(appPageDocument, selectors)
+	}
+	extraction.track(lastError, fieldPercentPerRating)
 
-			appPage.LastUpdate, lastError = getLastUpdate(appPageDocument)
-			if lastError != nil {
-				appPage.Errors = append(appPage.Errors, lastError.Error())
-			}
+	appPage.EstimatedDownloadNumber, appPage.InstallBucket, lastError = getEstimatedDownloadNumber(appPageDocument, selectors, locale)
+	extraction.track(lastError, fieldEstimatedDownloadNumber, fieldInstallBucket)
 
-			appPage.RequiresOsVersion, lastError = getRequiresOsVersion(appPageDocument)
-			if lastError != nil {
-				appPage.Errors = append(appPage.Errors, lastError.Error())
-			}
+	appPage.DeveloperName, lastError = getDeveloperName(appPageDocument, selectors, locale)
+	extraction.track(lastError, fieldDeveloperName)
 
-			appPage.CurrentSoftwareVersion, lastError = getCurrentSoftwareVersion(appPageDocument)
-			if lastError != nil {
-				appPage.Errors = append(appPage.Errors, lastError.Error())
-			}
-			// here the whole page is needed, not the app block
-			appPage.SimilarApps, lastError = getSimilarApps(document)
-			if lastError != nil {
-				appPage.Errors = append(appPage.Errors, lastError.Error())
-			}
-		} else {
-			appPage.Errors = append(appPage.Errors, appPageDocumentError.Error())
-		}
+	appPage.DeveloperID, lastError = getDeveloperID(appPageDocument, selectors)
+	extraction.track(lastError, fieldDeveloperID)
+
+	appPage.TopDeveloper, lastError = getTopDeveloper(appPageDocument, selectors)
+	extraction.track(lastError, fieldTopDeveloper)
+
+	appPage.ContainsAds, lastError = getContainsAds(appPageDocument, selectors, locale)
+	extraction.track(lastError, fieldContainsAds)
+
+	appPage.InAppPurchases, lastError = getInAppPurchases(appPageDocument, selectors, locale)
+	extraction.track(lastError, fieldInAppPurchases)
+
+	appPage.LastUpdate, lastError = getLastUpdate(appPageDocument, selectors, locale)
+	extraction.track(lastError, fieldLastUpdate)
+
+	appPage.ReleaseDate, lastError = getReleaseDate(appPageDocument, selectors, locale)
+	extraction.track(lastError, fieldReleaseDate)
+
+	appPage.RequiresOsVersion, lastError = getRequiresOsVersion(appPageDocument, selectors, locale)
+	extraction.track(lastError, fieldRequiresOsVersion)
+
+	appPage.CurrentSoftwareVersion, lastError = getCurrentSoftwareVersion(appPageDocument, selectors, locale)
+	extraction.track(lastError, fieldCurrentSoftwareVersion)
+
+	appPage.Size, lastError = getSize(appPageDocument, selectors, locale)
+	extraction.track(lastError, fieldSize)
+
+	appPage.ContentRatingDescriptors, lastError = getContentRatingDescriptors(appPageDocument, selectors, locale)
+	extraction.track(lastError, fieldContentRatingDescriptors)
+
+	appPage.InteractiveElements, lastError = getInteractiveElements(appPageDocument, selectors, locale)
+	extraction.track(lastError, fieldInteractiveElements)
+
+	appPage.InAppProducts, lastError = getInAppProducts(appPageDocument, selectors, locale)
+	extraction.track(lastError, fieldInAppProducts)
+
+	appPage.OfferedBy, lastError = getOfferedBy(appPageDocument, selectors, locale)
+	extraction.track(lastError, fieldOfferedBy)
+
+	appPage.DeveloperWebsite, appPage.DeveloperEmail, appPage.DeveloperAddress, appPage.PrivacyPolicyURL, lastError = getDeveloperContact(appPageDocument, selectors, locale)
+	extraction.track(lastError, fieldDeveloperWebsite, fieldDeveloperEmail, fieldDeveloperAddress, fieldPrivacyPolicyURL)
+
+	appPage.IconURL, lastError = getIconURL(appPageDocument, selectors)
+	extraction.track(lastError, fieldIconURL)
+
+	appPage.Screenshots, lastError = getScreenshots(appPageDocument, selectors)
+	extraction.track(lastError, fieldScreenshots)
+
+	appPage.FeatureGraphicURL, lastError = getFeatureGraphicURL(appPageDocument, selectors)
+	extraction.track(lastError, fieldFeatureGraphicURL)
+
+	appPage.PromoVideoID, lastError = getPromoVideoID(appPageDocument, selectors)
+	extraction.track(lastError, fieldPromoVideoID)
+
+	// here the whole page is needed, not the app block
+	appPage.SimilarApps, lastError = getSimilarApps(ctx, options.Fetcher, document, selectors)
+	extraction.track(lastError, fieldSimilarApps)
+
+	return extraction
+}
+
+// extracts the fields shown on pages or in dialogs of their own, they are only requested if the app page refers to them
+func extractDetails(ctx context.Context, document soup.Root, packageName string, options CrawlOptions) Extraction {
+	var lastError error
+	extraction := newExtraction(strategyNameDOM)
+	locale := getLocale(options.Language)
+	appPage := &extraction.AppPage
+
+	// only the redesigned app pages link to the data safety section, they don't list the permissions anymore either
+	linksDataSafety := hasDataSafetyLink(document)
+	if linksDataSafety {
+		appPage.DataSafety, lastError = getDataSafety(ctx, options.Fetcher, options.dataSafetyURL(packageName), options.Selectors, locale)
+		extraction.track(lastError, fieldDataSafety)
+	}
+	if _, permissionsError := getMainInformationBlockAdditionalEntry(document, options.Selectors, locale, "permissions", additionalPermissions); linksDataSafety || permissionsError == nil {
+		appPage.Permissions, lastError = getPermissions(ctx, packageName, options)
+		extraction.track(lastError, fieldPermissions)
 	}
 
-	return appPage
+	return extraction
 }
 
 // returns the object of the content area of app information
-func getPageDocument(document soup.Root) (soup.Root, error) {
-	pageDom := document.Find(div, class, classAppPage)
+func getPageDocument(document soup.Root, selectors SelectorProfile) (soup.Root, error) {
+	pageDom := document.Find(div, class, selectors.ClassAppPage)
 	var pageDomError error = nil
 	if pageDom.Error != nil {
 		pageDomError = errors.New(errorPageNotFound)
@@ -235,10 +394,10 @@ func getPageDocument(document soup.Root) (soup.Root, error) {
 }
 
 // returns the 3 main app information blocks : reviews, new functions, additional information
-func getMainInformationBlocks(document soup.Root) []soup.Root {
+func getMainInformationBlocks(document soup.Root, selectors SelectorProfile) []soup.Root {
 	var informationBlocks []soup.Root
 
-	informationBlockHeadlines := document.FindAll(h2, class, classMainInformationHeadline)
+	informationBlockHeadlines := document.FindAll(h2, class, selectors.ClassMainInformationHeadline)
 	for position := range informationBlockHeadlines {
 		if informationBlockHeadlines[position].Error == nil {
 			informationBlocks = append(informationBlocks, informationBlockHeadlines[position].FindParent().FindParent())
@@ -248,11 +407,11 @@ func getMainInformationBlocks(document soup.Root) []soup.Root {
 }
 
 // returns the requested information block child while using placeholder
-func getMainInformationBlockValidated(document soup.Root, position int, property string, blockType string) (soup.Root, error) {
+func getMainInformationBlockValidated(document soup.Root, selectors SelectorProfile, position int, property string, blockType string) (soup.Root, error) {
 	var informationBlock soup.Root
 	var informationBlockError error = nil
 
-	informationBlocks := getMainInformationBlocks(document)
+	informationBlocks := getMainInformationBlocks(document, selectors)
 	if len(informationBlocks) >= 3 {
 		informationBlockContainer := informationBlocks[position]
 		informationBlockChildren := informationBlockContainer.Children()
@@ -262,64 +421,64 @@ func getMainInformationBlockValidated(document soup.Root, position int, property
 			informationBlockError = errors.New(property + " : main information block \"" + blockType + "\" should contain at least 2 children")
 		}
 	} else {
-		informationBlockError = errors.New(property + " : main information blocks couldn't be found, looking for 2 levels above <h2 class=\"" + classMainInformationHeadline + "\"></h2>")
+		informationBlockError = errors.New(property + " : main information blocks couldn't be found, looking for 2 levels above <h2 class=\"" + selectors.ClassMainInformationHeadline + "\"></h2>")
 	}
 	return informationBlock, informationBlockError
 }
 
 // returns the app information block (basic information at the top of the site)
-func getMainInformationBlockApp(document soup.Root, property string) (soup.Root, error) {
+func getMainInformationBlockApp(document soup.Root, selectors SelectorProfile, property string) (soup.Root, error) {
 	var informationBlockApp soup.Root
 	var informationBlockAppError error = nil
 
-	informationBlock := document.Find(div, class, classMainInformationAppContainer)
+	informationBlock := document.Find(div, class, selectors.ClassMainInformationAppContainer)
 	if informationBlock.Error == nil {
-		informationBlockAppContainer := informationBlock.Find(div, class, classMainInformationApp)
+		informationBlockAppContainer := informationBlock.Find(div, class, selectors.ClassMainInformationApp)
 		if informationBlockAppContainer.Error == nil {
 			informationBlockApp = informationBlockAppContainer
 		} else {
-			informationBlockAppError = errors.New(property + " : main information block \"app\" should contain <div class=\"" + classMainInformationApp + "\"></div>")
+			informationBlockAppError = errors.New(property + " : main information block \"app\" should contain <div class=\"" + selectors.ClassMainInformationApp + "\"></div>")
 		}
 	} else {
-		informationBlockAppError = errors.New(property + " : main information block \"app\" couldn't be found, looking for <div class=\"" + classMainInformationAppContainer + "\"></div>")
+		informationBlockAppError = errors.New(property + " : main information block \"app\" couldn't be found, looking for <div class=\"" + selectors.ClassMainInformationAppContainer + "\"></div>")
 	}
 	return informationBlockApp, informationBlockAppError
 }
 
 // returns the similar information block (list of similar apps or apps from same developer)
-func getMainInformationBlockSimilar(document soup.Root, property string) (soup.Root, error) {
+func getMainInformationBlockSimilar(document soup.Root, selectors SelectorProfile, property string) (soup.Root, error) {
 	var informationBlockSimilar soup.Root
 	var informationBlockSimilarError error = nil
 
-	informationBlock := document.Find(div, class, classMainInformationSimilarContainer)
+	informationBlock := document.Find(div, class, selectors.ClassMainInformationSimilarContainer)
 	if informationBlock.Error == nil {
 		informationBlockSimilar = informationBlock
 	} else {
-		informationBlockSimilarError = errors.New(property + " : main information block \"similar apps\" couldn't be found, looking for <div class=\"" + classMainInformationSimilarContainer + "\"></div>")
+		informationBlockSimilarError = errors.New(property + " : main information block \"similar apps\" couldn't be found, looking for <div class=\"" + selectors.ClassMainInformationSimilarContainer + "\"></div>")
 	}
 	return informationBlockSimilar, informationBlockSimilarError
 }
 
 // returns the similar information block (list of similar apps or apps from same developer)
-func getMainInformationBlockSimilarChildren(document soup.Root, property string) ([]soup.Root, error) {
+func getMainInformationBlockSimilarChildren(ctx context.Context, fetcher Fetcher, document soup.Root, selectors SelectorProfile, property string) ([]soup.Root, error) {
 	var informationBlockSimilarChildren []soup.Root
 	var informationBlockSimilarChildrenError error = nil
 
-	informationBlockSimilar, informationBlockSimilarError := getMainInformationBlockSimilar(document, property)
+	informationBlockSimilar, informationBlockSimilarError := getMainInformationBlockSimilar(document, selectors, property)
 	if informationBlockSimilarError == nil {
 		informationBlockSimilarLink := informationBlockSimilar.Find(a)
 		if informationBlockSimilarLink.Error == nil && informationBlockSimilarLink.HasAttribute(href) && informationBlockSimilarLink.GetAttribute(href) != "" {
-			similarAppsPageHTML, similarAppsPageHTMLError := soup.Get(baseURL + informationBlockSimilarLink.GetAttribute(href))
-			if similarAppsPageHTMLError == nil {
-				similarAppsDocument := soup.HTMLParse(similarAppsPageHTML)
-				similarAppsAreas := similarAppsDocument.Find(div, class, classMainInformationSimilar)
+			similarAppsPage, similarAppsPageError := fetcher.Fetch(ctx, baseURL+informationBlockSimilarLink.GetAttribute(href))
+			if similarAppsPageError == nil && similarAppsPage.StatusCode == http.StatusOK {
+				similarAppsDocument := soup.HTMLParse(similarAppsPage.Body)
+				similarAppsAreas := similarAppsDocument.Find(div, class, selectors.ClassMainInformationSimilar)
 				if similarAppsAreas.Error == nil {
 					informationBlockSimilarChildren = similarAppsAreas.Children()
 				}
 			}
 		}
 		if len(informationBlockSimilarChildren) == 0 {
-			similarAppsAreas := informationBlockSimilar.Find(div, class, classMainInformationSimilar)
+			similarAppsAreas := informationBlockSimilar.Find(div, class, selectors.ClassMainInformationSimilar)
 			if similarAppsAreas.Error == nil {
 				informationBlockSimilarChildren = similarAppsAreas.Children()
 			}
@@ -331,35 +490,35 @@ func getMainInformationBlockSimilarChildren(document soup.Root, property string)
 }
 
 // returns the review information block
-func getMainInformationBlockReview(document soup.Root, property string) (soup.Root, error) {
-	return getMainInformationBlockValidated(document, 0, property, blockTypeReview)
+func getMainInformationBlockReview(document soup.Root, selectors SelectorProfile, property string) (soup.Root, error) {
+	return getMainInformationBlockValidated(document, selectors, 0, property, blockTypeReview)
 }
 
 // returns the whats new information block
-func getMainInformationBlockWhatsNew(document soup.Root, property string) (soup.Root, error) {
-	return getMainInformationBlockValidated(document, 1, property, blockTypeWhatsNew)
+func getMainInformationBlockWhatsNew(document soup.Root, selectors SelectorProfile, property string) (soup.Root, error) {
+	return getMainInformationBlockValidated(document, selectors, 1, property, blockTypeWhatsNew)
 }
 
 // returns the additional information block
-func getMainInformationBlockAdditional(document soup.Root, property string) (soup.Root, error) {
-	return getMainInformationBlockValidated(document, 2, property, blockTypeAdditional)
+func getMainInformationBlockAdditional(document soup.Root, selectors SelectorProfile, property string) (soup.Root, error) {
+	return getMainInformationBlockValidated(document, selectors, 2, property, blockTypeAdditional)
 }
 
 // returns the additional information block children (updated, size, installs etc.)
-func getMainInformationBlockAdditionalChildren(document soup.Root, property string) ([]soup.Root, error) {
+func getMainInformationBlockAdditionalChildren(document soup.Root, selectors SelectorProfile, property string) ([]soup.Root, error) {
 	var informationAdditionalChildren []soup.Root
 	var informationAdditionalChildrenError error = nil
 
-	informationBlockAdditional, informationBlockAdditionalError := getMainInformationBlockAdditional(document, property)
+	informationBlockAdditional, informationBlockAdditionalError := getMainInformationBlockAdditional(document, selectors, property)
 	if informationBlockAdditionalError == nil {
-		informationBlockAdditionalContainer := informationBlockAdditional.Find(div, class, classMainInformationAdditionalContainer)
+		informationBlockAdditionalContainer := informationBlockAdditional.Find(div, class, selectors.ClassMainInformationAdditionalContainer)
 		if informationBlockAdditionalContainer.Error == nil {
 			informationAdditionalChildren = informationBlockAdditionalContainer.Children()
-			if len(informationAdditionalChildren) < 11 {
-				informationAdditionalChildrenError = errors.New(property + " : <div class=\"" + classMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" should contain at least 11 children")
+			if len(informationAdditionalChildren) == 0 {
+				informationAdditionalChildrenError = errors.New(property + " : <div class=\"" + selectors.ClassMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" doesn't contain any entries")
 			}
 		} else {
-			informationAdditionalChildrenError = errors.New(property + " : there is no <div class=\"" + classMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\"")
+			informationAdditionalChildrenError = errors.New(property + " : there is no <div class=\"" + selectors.ClassMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\"")
 		}
 	} else {
 		informationAdditionalChildrenError = informationBlockAdditionalError
@@ -367,41 +526,27 @@ func getMainInformationBlockAdditionalChildren(document soup.Root, property stri
 	return informationAdditionalChildren, informationAdditionalChildrenError
 }
 
-// returns specific additional information block child (for example "updated")
-func getMainInformationBlockAdditionalChild(document soup.Root, property string, position int) (soup.Root, error) {
-	var informationAdditionalChild soup.Root
-	var informationAdditionalChildError error = nil
-
-	informationBlockAdditionalChildren, informationBlockAdditionalChildrenError := getMainInformationBlockAdditionalChildren(document, property)
-	if informationBlockAdditionalChildrenError == nil {
-		informationAdditionalChild = informationBlockAdditionalChildren[position]
-	} else {
-		informationAdditionalChildError = informationBlockAdditionalChildrenError
-	}
-	return informationAdditionalChild, informationAdditionalChildError
-}
-
 // returns the name of the app
-func getAppName(document soup.Root) (string, error) {
+func getAppName(document soup.Root, selectors SelectorProfile) (string, error) {
 	property := "appName"
 	appName := ""
 	var appNameError error = nil
 
-	informationBlockApp, informationBlockAppError := getMainInformationBlockApp(document, property)
+	informationBlockApp, informationBlockAppError := getMainInformationBlockApp(document, selectors, property)
 	if informationBlockAppError == nil {
-		headline := informationBlockApp.Find(h1, itemprop, itempropAppName)
+		headline := informationBlockApp.Find(h1, itemprop, selectors.ItempropAppName)
 		if headline.Error == nil {
 			headlineSpan := headline.Find(span)
 			if headlineSpan.Error == nil {
 				appName = headlineSpan.Text()
 				if appName == "" {
-					appNameError = errors.New(property + " : span inside of <h1 itemprop=\"" + itempropAppName + "\"></h1> is empty")
+					appNameError = errors.New(property + " : span inside of <h1 itemprop=\"" + selectors.ItempropAppName + "\"></h1> is empty")
 				}
 			} else {
-				appNameError = errors.New(property + " : there is no span inside of <h1 itemprop=\"" + itempropAppName + "\"></h1>")
+				appNameError = errors.New(property + " : there is no span inside of <h1 itemprop=\"" + selectors.ItempropAppName + "\"></h1>")
 			}
 		} else {
-			appNameError = errors.New(property + " : there is no <h1 itemprop=\"" + itempropAppName + "\"></h1>")
+			appNameError = errors.New(property + " : there is no <h1 itemprop=\"" + selectors.ItempropAppName + "\"></h1>")
 		}
 	} else {
 		appNameError = informationBlockAppError
@@ -411,21 +556,21 @@ func getAppName(document soup.Root) (string, error) {
 }
 
 // returns the category of the app
-func getCategory(document soup.Root) (string, error) {
+func getCategory(document soup.Root, selectors SelectorProfile) (string, error) {
 	property := "category"
 	category := ""
 	var categoryError error = nil
 
-	informationBlockApp, informationBlockAppError := getMainInformationBlockApp(document, property)
+	informationBlockApp, informationBlockAppError := getMainInformationBlockApp(document, selectors, property)
 	if informationBlockAppError == nil {
-		categoryElement := informationBlockApp.Find(a, itemprop, itempropAppCategory)
+		categoryElement := informationBlockApp.Find(a, itemprop, selectors.ItempropAppCategory)
 		if categoryElement.Error == nil {
 			category = categoryElement.Text()
 			if category == "" {
-				categoryError = errors.New(property + " : <a itemprop=\"" + itempropAppCategory + "\"></a> is empty")
+				categoryError = errors.New(property + " : <a itemprop=\"" + selectors.ItempropAppCategory + "\"></a> is empty")
 			}
 		} else {
-			categoryError = errors.New(property + " : there is no <a itemprop=\"" + itempropAppCategory + "\"></a>")
+			categoryError = errors.New(property + " : there is no <a itemprop=\"" + selectors.ItempropAppCategory + "\"></a>")
 		}
 	} else {
 		categoryError = informationBlockAppError
@@ -435,14 +580,14 @@ func getCategory(document soup.Root) (string, error) {
 }
 
 // returns the USK of the app
-func getUsk(document soup.Root) (string, error) {
+func getUsk(document soup.Root, selectors SelectorProfile) (string, error) {
 	property := "usk"
 	usk := ""
 	var uskError error = nil
 
-	informationBlockApp, informationBlockAppError := getMainInformationBlockApp(document, property)
+	informationBlockApp, informationBlockAppError := getMainInformationBlockApp(document, selectors, property)
 	if informationBlockAppError == nil {
-		elementCategoryUsk := informationBlockApp.Find(div, class, classAppCategoryUsk)
+		elementCategoryUsk := informationBlockApp.Find(div, class, selectors.ClassAppCategoryUsk)
 		if elementCategoryUsk.Error == nil {
 			elementCategoryUskChildren := elementCategoryUsk.Children()
 			if len(elementCategoryUskChildren) >= 2 {
@@ -451,16 +596,16 @@ func getUsk(document soup.Root) (string, error) {
 				if uskImage.Error == nil {
 					usk = uskImage.GetAttribute(alt)
 					if usk == "" {
-						uskError = errors.New(property + " : the alt of the image of second child of <div class=\"" + classAppCategoryUsk + "\"></div> is empty")
+						uskError = errors.New(property + " : the alt of the image of second child of <div class=\"" + selectors.ClassAppCategoryUsk + "\"></div> is empty")
 					}
 				} else {
-					uskError = errors.New(property + " : the second child of <div class=\"" + classAppCategoryUsk + "\"></div> should contain an image some levels lower")
+					uskError = errors.New(property + " : the second child of <div class=\"" + selectors.ClassAppCategoryUsk + "\"></div> should contain an image some levels lower")
 				}
 			} else {
-				uskError = errors.New(property + " : there should be at least 2 children in <div class=\"" + classAppCategoryUsk + "\"></div>")
+				uskError = errors.New(property + " : there should be at least 2 children in <div class=\"" + selectors.ClassAppCategoryUsk + "\"></div>")
 			}
 		} else {
-			uskError = errors.New(property + " : there is no <div class=\"" + classAppCategoryUsk + "\"></div> in main information block \"app\"")
+			uskError = errors.New(property + " : there is no <div class=\"" + selectors.ClassAppCategoryUsk + "\"></div> in main information block \"app\"")
 		}
 	} else {
 		uskError = informationBlockAppError
@@ -470,16 +615,16 @@ func getUsk(document soup.Root) (string, error) {
 }
 
 // returns the marker (free or paid), the price of the app and the currency
-func getPrice(document soup.Root) (string, float64, string, error) {
+func getPrice(document soup.Root, selectors SelectorProfile, locale Locale) (string, float64, string, error) {
 	property := "price"
 	var price string
 	var priceValue float64
 	var priceCurrency string
 	var priceError error = nil
 
-	informationBlockApp, informationBlockAppError := getMainInformationBlockApp(document, property)
+	informationBlockApp, informationBlockAppError := getMainInformationBlockApp(document, selectors, property)
 	if informationBlockAppError == nil {
-		blockPrice := informationBlockApp.Find(meta, itemprop, itempropAppPrice)
+		blockPrice := informationBlockApp.Find(meta, itemprop, selectors.ItempropAppPrice)
 		if blockPrice.Error == nil {
 			if blockPrice.HasAttribute(content) {
 				attributeContent := blockPrice.GetAttribute(content)
@@ -492,12 +637,8 @@ func getPrice(document soup.Root) (string, float64, string, error) {
 					priceValue = 0
 					priceCurrency = ""
 				} else {
-					priceCurrency = string([]rune(attributeContent)[0])
-
-					p := strings.Split(attributeContent, priceCurrency)[1]
-					p = strings.Replace(p, ",", ".", -1)
-					//fmt.Println(p)
-					priceParsed, parseError := strconv.ParseFloat(p, -1)
+					priceParsed, currencyParsed, parseError := locale.parsePrice(attributeContent)
+					priceCurrency = currencyParsed
 					if parseError == nil {
 						priceValue = priceParsed
 					} else {
@@ -505,10 +646,10 @@ func getPrice(document soup.Root) (string, float64, string, error) {
 					}
 				}
 			} else {
-				priceError = errors.New(property + " : <meta itemprop=\"" + itempropAppPrice + "\"></meta> should contain attribute \"" + content + "\"")
+				priceError = errors.New(property + " : <meta itemprop=\"" + selectors.ItempropAppPrice + "\"></meta> should contain attribute \"" + content + "\"")
 			}
 		} else {
-			priceError = errors.New(property + " : there is no <meta itemprop=\"" + itempropAppPrice + "\"></meta> in main information block \"app\"")
+			priceError = errors.New(property + " : there is no <meta itemprop=\"" + selectors.ItempropAppPrice + "\"></meta> in main information block \"app\"")
 		}
 	} else {
 		priceError = informationBlockAppError
@@ -518,34 +659,34 @@ func getPrice(document soup.Root) (string, float64, string, error) {
 }
 
 // returns the description of the app
-func getDescription(doc soup.Root) (string, error) {
+func getDescription(doc soup.Root, selectors SelectorProfile) (string, error) {
 	description := ""
 	var descriptionError error = nil
 
-	blockDescription := doc.Find(div, itemprop, itempropAppDescription)
+	blockDescription := doc.Find(div, itemprop, selectors.ItempropAppDescription)
 	if blockDescription.Error == nil {
 		descriptionElement := blockDescription.Find(div)
 		if descriptionElement.Error == nil {
 			description = descriptionElement.Text()
 			if description == "" {
-				descriptionError = errors.New("description : the first div below <div itemprop=\"" + itempropAppDescription + "\"></div> is empty")
+				descriptionError = errors.New("description : the first div below <div itemprop=\"" + selectors.ItempropAppDescription + "\"></div> is empty")
 			}
 		} else {
-			descriptionError = errors.New("description : <div itemprop=\"" + itempropAppDescription + "\"></div> should contain an div some levels lower")
+			descriptionError = errors.New("description : <div itemprop=\"" + selectors.ItempropAppDescription + "\"></div> should contain an div some levels lower")
 		}
 	} else {
-		descriptionError = errors.New("description : there is no <div itemprop=\"" + itempropAppDescription + "\"></meta>")
+		descriptionError = errors.New("description : there is no <div itemprop=\"" + selectors.ItempropAppDescription + "\"></meta>")
 	}
 
 	return description, descriptionError
 }
 
 // returns a list of entries what is new in the app
-func getWhatsNew(document soup.Root) ([]string, error) {
+func getWhatsNew(document soup.Root, selectors SelectorProfile) ([]string, error) {
 	var whatsNew []string
 	var whatsNewError error = nil
 
-	informationBlock, informationBlockError := getMainInformationBlockWhatsNew(document, "whatsNew")
+	informationBlock, informationBlockError := getMainInformationBlockWhatsNew(document, selectors, "whatsNew")
 	if informationBlockError == nil {
 		whatsNewContainer := informationBlock.Find(span)
 		if whatsNewContainer.Error == nil {
@@ -566,27 +707,27 @@ func getWhatsNew(document soup.Root) ([]string, error) {
 }
 
 // returns the star rating of the app
-func getRating(document soup.Root) (float64, error) {
+func getRating(document soup.Root, selectors SelectorProfile, locale Locale) (float64, error) {
 	var rating float64 = 0
 	var ratingError error = nil
 
-	informationBlockReview, informationBlockReviewError := getMainInformationBlockReview(document, "rating")
+	informationBlockReview, informationBlockReviewError := getMainInformationBlockReview(document, selectors, "rating")
 	if informationBlockReviewError == nil {
-		ratingContainer := informationBlockReview.Find(div, class, classAppRating)
+		ratingContainer := informationBlockReview.Find(div, class, selectors.ClassAppRating)
 		if ratingContainer.Error == nil {
 			ratingString := ratingContainer.Text()
 			if ratingString != "" {
-				ratingFloat, parseError := strconv.ParseFloat(ratingString, 64)
+				ratingFloat, parseError := locale.parseDecimal(ratingString)
 				if parseError == nil {
 					rating = ratingFloat
 				} else {
-					ratingError = errors.New("rating : <div class=\"" + classAppRating + "\"></div> is not a float and contains \"" + ratingString + "\"")
+					ratingError = errors.New("rating : <div class=\"" + selectors.ClassAppRating + "\"></div> is not a float and contains \"" + ratingString + "\"")
 				}
 			} else {
-				ratingError = errors.New("rating : <div class=\"" + classAppRating + "\"></div> is empty")
+				ratingError = errors.New("rating : <div class=\"" + selectors.ClassAppRating + "\"></div> is empty")
 			}
 		} else {
-			ratingError = errors.New("rating : there is no <div class=\"" + classAppRating + "\"></div> inside of main information block \"reviews\"")
+			ratingError = errors.New("rating : there is no <div class=\"" + selectors.ClassAppRating + "\"></div> inside of main information block \"reviews\"")
 		}
 	} else {
 		ratingError = informationBlockReviewError
@@ -596,13 +737,13 @@ func getRating(document soup.Root) (float64, error) {
 }
 
 // returns the amount of the stars for the app
-func getStarsCount(document soup.Root) (int64, error) {
+func getStarsCount(document soup.Root, selectors SelectorProfile) (int64, error) {
 	var starsCount int64 = 0
 	var starsCountError error = nil
 
-	informationBlockReview, informationBlockReviewError := getMainInformationBlockReview(document, "starsCount")
+	informationBlockReview, informationBlockReviewError := getMainInformationBlockReview(document, selectors, "starsCount")
 	if informationBlockReviewError == nil {
-		starsCountContainer := informationBlockReview.Find(span, class, classAppStarsCount)
+		starsCountContainer := informationBlockReview.Find(span, class, selectors.ClassAppStarsCount)
 		if starsCountContainer.Error == nil {
 			starsCountContainerChildren := starsCountContainer.Children()
 			if len(starsCountContainerChildren) >= 2 {
@@ -614,16 +755,16 @@ func getStarsCount(document soup.Root) (int64, error) {
 					if parseError == nil {
 						starsCount = starsCountNumber
 					} else {
-						starsCountError = errors.New("starsCount : <span class=\"" + classAppStarsCount + "\"></span> is not an integer and contains \"" + starsCountString + "\"")
+						starsCountError = errors.New("starsCount : <span class=\"" + selectors.ClassAppStarsCount + "\"></span> is not an integer and contains \"" + starsCountString + "\"")
 					}
 				} else {
-					starsCountError = errors.New("starsCount : <span class=\"" + classAppStarsCount + "\"></span> is empty")
+					starsCountError = errors.New("starsCount : <span class=\"" + selectors.ClassAppStarsCount + "\"></span> is empty")
 				}
 			} else {
-				starsCountError = errors.New("starsCount : <span class=\"" + classAppStarsCount + "\"></span> should contain at least 2 children")
+				starsCountError = errors.New("starsCount : <span class=\"" + selectors.ClassAppStarsCount + "\"></span> should contain at least 2 children")
 			}
 		} else {
-			starsCountError = errors.New("starsCount : there is no <span class=\"" + classAppStarsCount + "\"></span> inside of main information block \"reviews\"")
+			starsCountError = errors.New("starsCount : there is no <span class=\"" + selectors.ClassAppStarsCount + "\"></span> inside of main information block \"reviews\"")
 		}
 	} else {
 		starsCountError = informationBlockReviewError
@@ -632,95 +773,151 @@ func getStarsCount(document soup.Root) (int64, error) {
 	return starsCount, starsCountError
 }
 
-// returns the distribution in percentage between the amount of stars you can give based on the current rating
-func getCountPerRating(document soup.Root) (StarCountPerRating, error) {
-	countPerRating := StarCountPerRating{}
-	var countPerRatingError error = nil
+// returns the bars of the rating histogram by the number of stars they stand for
+func getRatingBars(document soup.Root, selectors SelectorProfile, property string) (map[string]soup.Root, error) {
+	ratingBars := map[string]soup.Root{}
+	var ratingBarsError error = nil
 
-	informationBlockReview, informationBlockReviewError := getMainInformationBlockReview(document, "countPerRating")
+	informationBlockReview, informationBlockReviewError := getMainInformationBlockReview(document, selectors, property)
 	if informationBlockReviewError == nil {
-		countPerRatingContainer := informationBlockReview.Find(div, class, classAppCountPerRating)
-		if countPerRatingContainer.Error == nil {
-			countPerRatingElements := countPerRatingContainer.Children()
-			if len(countPerRatingElements) >= 5 {
-				for position := range countPerRatingElements {
-					countPerRatingElementChildren := countPerRatingElements[position].Children()
-					if len(countPerRatingElementChildren) >= 2 {
-						rating := countPerRatingElementChildren[0].Text()
-						width := 0
+		ratingBarsContainer := informationBlockReview.Find(div, class, selectors.ClassAppCountPerRating)
+		if ratingBarsContainer.Error == nil {
+			ratingBarsElements := ratingBarsContainer.Children()
+			if len(ratingBarsElements) >= 5 {
+				for position := range ratingBarsElements {
+					ratingBarsElementChildren := ratingBarsElements[position].Children()
+					if len(ratingBarsElementChildren) >= 2 {
+						rating := strings.TrimSpace(ratingBarsElementChildren[0].Text())
 						if rating != "" {
-							if countPerRatingElementChildren[1].HasAttribute(style) {
-								style := countPerRatingElementChildren[1].GetAttribute("style")
-								styleParts := strings.Split(style, ";")
-								for positionStyle := range styleParts {
-									styleDefinition := AttributeStyle{}.fill(styleParts[positionStyle])
-									if styleDefinition.Name == styleWidth {
-										width = styleDefinition.getValueAsInt()
-										switch rating {
-										case "1":
-											countPerRating.One = width
-											break
-										case "2":
-											countPerRating.Two = width
-											break
-										case "3":
-											countPerRating.Three = width
-											break
-										case "4":
-											countPerRating.Four = width
-											break
-										case "5":
-											countPerRating.Five = width
-											break
-										}
-										break
-									}
-								}
-							}
+							ratingBars[rating] = ratingBarsElementChildren[1]
 						} else {
-							countPerRatingError = errors.New("countPerRating : final element doesn't contain a rating")
+							ratingBarsError = errors.New(property + " : final element doesn't contain a rating")
 						}
 					} else {
-						countPerRatingError = errors.New("countPerRating : child of <div class=\"" + classAppCountPerRating + "\"></div> in main information block \"reviews\" should have at least 2 children")
+						ratingBarsError = errors.New(property + " : child of <div class=\"" + selectors.ClassAppCountPerRating + "\"></div> in main information block \"reviews\" should have at least 2 children")
 					}
 				}
 			} else {
-				countPerRatingError = errors.New("countPerRating : <div class=\"" + classAppCountPerRating + "\"></div> in main information block \"reviews\" should have at least 5 children")
+				ratingBarsError = errors.New(property + " : <div class=\"" + selectors.ClassAppCountPerRating + "\"></div> in main information block \"reviews\" should have at least 5 children")
 			}
 		} else {
-			countPerRatingError = errors.New("countPerRating : there is no <div class=\"" + classAppCountPerRating + "\"></div> in main information block \"reviews\"")
+			ratingBarsError = errors.New(property + " : there is no <div class=\"" + selectors.ClassAppCountPerRating + "\"></div> in main information block \"reviews\"")
 		}
 	} else {
-		countPerRatingError = informationBlockReviewError
+		ratingBarsError = informationBlockReviewError
 	}
 
-	ratingWidthSum := float64(countPerRating.One + countPerRating.Two + countPerRating.Three + countPerRating.Four + countPerRating.Five)
-	countPerRating.One = int(math.Round(float64(countPerRating.One) / ratingWidthSum * 100))
-	countPerRating.Two = int(math.Round(float64(countPerRating.Two) / ratingWidthSum * 100))
-	countPerRating.Three = int(math.Round(float64(countPerRating.Three) / ratingWidthSum * 100))
-	countPerRating.Four = int(math.Round(float64(countPerRating.Four) / ratingWidthSum * 100))
-	countPerRating.Five = int(math.Round(float64(countPerRating.Five) / ratingWidthSum * 100))
+	return ratingBars, ratingBarsError
+}
+
+// returns the number of ratings with each amount of stars, the bars of the histogram name it in their title or label
+func getCountPerRating(document soup.Root, selectors SelectorProfile, locale Locale) (StarCountPerRating, error) {
+	countPerRating := StarCountPerRating{}
 
-	return countPerRating, countPerRatingError
+	ratingBars, ratingBarsError := getRatingBars(document, selectors, "countPerRating")
+	if ratingBarsError != nil {
+		return countPerRating, ratingBarsError
+	}
+	for _, rating := range []string{"1", "2", "3", "4", "5"} {
+		ratingBar, exists := ratingBars[rating]
+		if !exists {
+			return countPerRating, errors.New("countPerRating : there is no bar for " + rating + " stars")
+		}
+		countString := ratingBar.GetAttribute(title)
+		if countString == "" {
+			countString = ratingBar.GetAttribute(ariaLabel)
+		}
+		count, parseError := locale.parseInteger(countString)
+		if parseError != nil {
+			return countPerRating, errors.New("countPerRating : bar for " + rating + " stars doesn't contain the number of ratings")
+		}
+		switch rating {
+		case "1":
+			countPerRating.One = count
+		case "2":
+			countPerRating.Two = count
+		case "3":
+			countPerRating.Three = count
+		case "4":
+			countPerRating.Four = count
+		case "5":
+			countPerRating.Five = count
+		}
+	}
+
+	return countPerRating, nil
 }
 
-// returns the estimated number of downloads of the app
-func getEstimatedDownloadNumber(document soup.Root) (int64, error) {
+// returns the share of the ratings with each amount of stars in percent
+func getPercentPerRating(countPerRating StarCountPerRating) StarPercentPerRating {
+	percentPerRating := StarPercentPerRating{}
+	countSum := float64(countPerRating.One + countPerRating.Two + countPerRating.Three + countPerRating.Four + countPerRating.Five)
+	if countSum > 0 {
+		percentPerRating.One = int(math.Round(float64(countPerRating.One) / countSum * 100))
+		percentPerRating.Two = int(math.Round(float64(countPerRating.Two) / countSum * 100))
+		percentPerRating.Three = int(math.Round(float64(countPerRating.Three) / countSum * 100))
+		percentPerRating.Four = int(math.Round(float64(countPerRating.Four) / countSum * 100))
+		percentPerRating.Five = int(math.Round(float64(countPerRating.Five) / countSum * 100))
+	}
+
+	return percentPerRating
+}
+
+// returns the share of the ratings with each amount of stars in percent, estimated from the widths of the histogram
+// bars for pages which don't name the number of ratings
+func getPercentPerRatingFromWidths(document soup.Root, selectors SelectorProfile) (StarPercentPerRating, error) {
+	widths := StarCountPerRating{}
+
+	ratingBars, ratingBarsError := getRatingBars(document, selectors, "percentPerRating")
+	if ratingBarsError != nil {
+		return StarPercentPerRating{}, ratingBarsError
+	}
+	for rating, ratingBar := range ratingBars {
+		width := 0
+		if ratingBar.HasAttribute(style) {
+			styleParts := strings.Split(ratingBar.GetAttribute(style), ";")
+			for positionStyle := range styleParts {
+				styleDefinition := AttributeStyle{}.fill(styleParts[positionStyle])
+				if styleDefinition.Name == styleWidth {
+					width = styleDefinition.getValueAsInt()
+					break
+				}
+			}
+		}
+		switch rating {
+		case "1":
+			widths.One = int64(width)
+		case "2":
+			widths.Two = int64(width)
+		case "3":
+			widths.Three = int64(width)
+		case "4":
+			widths.Four = int64(width)
+		case "5":
+			widths.Five = int64(width)
+		}
+	}
+
+	return getPercentPerRating(widths), nil
+}
+
+// returns the lower bound of the install bucket the app is in, e.g. 1000000000 for "1,000,000,000+" or "1B+", together
+// with the normalized label of the bucket
+func getEstimatedDownloadNumber(document soup.Root, selectors SelectorProfile, locale Locale) (int64, string, error) {
 	var estimatedDownloadNumber int64 = 0
+	installBucket := ""
 	var estimatedDownloadNumberError error = nil
-	childPosition := 2
 
-	informationBlockAdditionalChild, informationBlockAdditionalChildError := getMainInformationBlockAdditionalChild(document, "estimatedDownloadNumber", childPosition)
-	if informationBlockAdditionalChildError == nil {
-		estimatedDownloadNumberElement := informationBlockAdditionalChild.FindAll(span)
+	informationBlockAdditionalEntry, informationBlockAdditionalEntryError := getMainInformationBlockAdditionalEntry(document, selectors, locale, "estimatedDownloadNumber", additionalInstalls)
+	if informationBlockAdditionalEntryError == nil {
+		estimatedDownloadNumberElement := informationBlockAdditionalEntry.FindAll(span)
 		if len(estimatedDownloadNumberElement) > 0 {
 			estimatedDownloadNumberString := estimatedDownloadNumberElement[len(estimatedDownloadNumberElement)-1].Text()
 			if estimatedDownloadNumberString != "" {
-				estimatedDownloadNumberStringReplaced := strings.Replace(estimatedDownloadNumberString, ",", "", -1)
-				estimatedDownloadNumberStringReplaced = strings.Replace(estimatedDownloadNumberStringReplaced, "+", "", -1)
-				estimatedDownloadNumberInt, parseError := strconv.ParseInt(strings.TrimSpace(estimatedDownloadNumberStringReplaced), 0, 64)
+				estimatedDownloadNumberInt, parseError := locale.parseInstalls(estimatedDownloadNumberString)
 				if parseError == nil {
 					estimatedDownloadNumber = estimatedDownloadNumberInt
+					installBucket = getInstallBucket(estimatedDownloadNumber)
 				} else {
 					estimatedDownloadNumberError = errors.New("estimatedDownloadNumber : final element doesn't contain a number of downloads, it contains : \"" + estimatedDownloadNumberString + "\"")
 				}
@@ -728,46 +925,61 @@ func getEstimatedDownloadNumber(document soup.Root) (int64, error) {
 				estimatedDownloadNumberError = errors.New("estimatedDownloadNumber : final element doesn't contain a number of downloads")
 			}
 		} else {
-			estimatedDownloadNumberError = errors.New("estimatedDownloadNumber : " + string(childPosition+1) + ". child of <div class=\"" + classMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" should contain at least one span at lower levels")
+			estimatedDownloadNumberError = errors.New("estimatedDownloadNumber : entry \"" + additionalInstalls + "\" of <div class=\"" + selectors.ClassMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" should contain at least one span at lower levels")
 		}
 	} else {
-		estimatedDownloadNumberError = informationBlockAdditionalChildError
+		estimatedDownloadNumberError = informationBlockAdditionalEntryError
+	}
+
+	return estimatedDownloadNumber, installBucket, estimatedDownloadNumberError
+}
+
+// returns the label of the install bucket starting at the number in the same format for every language, e.g.
+// "1,000,000+"
+func getInstallBucket(minInstalls int64) string {
+	digits := strconv.FormatInt(minInstalls, 10)
+	var label strings.Builder
+	for position, digit := range digits {
+		if position > 0 && (len(digits)-position)%3 == 0 {
+			label.WriteString(",")
+		}
+		label.WriteRune(digit)
 	}
 
-	return estimatedDownloadNumber, estimatedDownloadNumberError
+	return label.String() + "+"
 }
 
 // returns the link to the developer website
-func getDeveloperName(document soup.Root) (string, error) {
+func getDeveloperName(document soup.Root, selectors SelectorProfile, locale Locale) (string, error) {
 	developerName := ""
 	var developerNameError error = nil
 
-	informationBlockAdditionalChildren, informationBlockAdditionalChildrenError := getMainInformationBlockAdditionalChildren(document, "developerName")
-	if informationBlockAdditionalChildrenError == nil {
-		developerNameLink := informationBlockAdditionalChildren[len(informationBlockAdditionalChildren)-1].Find(a)
+	informationBlockAdditionalEntry, informationBlockAdditionalEntryError := getMainInformationBlockAdditionalEntry(document, selectors, locale, "developerName", additionalDeveloper)
+	if informationBlockAdditionalEntryError == nil {
+		developerNameLink := informationBlockAdditionalEntry.Find(a)
 		if developerNameLink.Error == nil {
 			if developerNameLink.HasAttribute(href) == true && developerNameLink.GetAttribute(href) != "" {
 				developerName = developerNameLink.GetAttribute("href")
 			} else {
-				developerNameError = errors.New("developerName : the link in <div class=\"" + classMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" doesn't have \"href\" Attribute or its empty")
+				developerNameError = errors.New("developerName : the link in <div class=\"" + selectors.ClassMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" doesn't have \"href\" Attribute or its empty")
 			}
 		} else {
-			developerNameError = errors.New("developerName : <div class=\"" + classMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" should contain a link at some lower levels")
+			developerNameError = errors.New("developerName : <div class=\"" + selectors.ClassMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" should contain a link at some lower levels")
 		}
 	} else {
-		developerNameError = informationBlockAdditionalChildrenError
+		developerNameError = informationBlockAdditionalEntryError
 	}
 	return developerName, developerNameError
 }
 
 // returns the badge if the app was marked as "redaction suggestion"
-func getTopDeveloper(document soup.Root) (bool, error) {
+func getTopDeveloper(document soup.Root, selectors SelectorProfile) (bool, error) {
 	topDeveloper := false
 	var topDeveloperError error = nil
 
-	informationBlockApp, informationBlockAppError := getMainInformationBlockApp(document, "topDeveloper")
+	informationBlockApp, informationBlockAppError := getMainInformationBlockApp(document, selectors, "topDeveloper")
 	if informationBlockAppError == nil {
-		topDeveloper = informationBlockApp.Find(meta, itemprop, itempropAppTopDeveloper).Error == nil
+		topDeveloper = informationBlockApp.Find(meta, itemprop, selectors.ItempropAppTopDeveloper).Error == nil
 	} else {
 		topDeveloperError = informationBlockAppError
 	}
@@ -775,27 +987,27 @@ func getTopDeveloper(document soup.Root) (bool, error) {
 }
 
 // returns if the app has advertisements or not
-func getContainsAds(document soup.Root) (bool, error) {
+func getContainsAds(document soup.Root, selectors SelectorProfile, locale Locale) (bool, error) {
 	containsAds := false
 	var containsAdsError error = nil
 
-	informationBlockApp, informationBlockAppError := getMainInformationBlockApp(document, "containsAds")
+	informationBlockApp, informationBlockAppError := getMainInformationBlockApp(document, selectors, "containsAds")
 	if informationBlockAppError == nil {
-		containsAdsBlock := informationBlockApp.Find(div, class, classAppContainsAds)
+		containsAdsBlock := informationBlockApp.Find(div, class, selectors.ClassAppContainsAds)
 		if containsAdsBlock.Error == nil {
 			containsAdsBlockChildren := containsAdsBlock.Children(true)
-			if len(containsAdsBlockChildren) == 0 && containsAdsBlock.Text() == valueContainsAds {
+			if len(containsAdsBlockChildren) == 0 && locale.matchesLabel(containsAdsBlock.Text(), selectors.ValueContainsAds) {
 				containsAds = true
 			} else {
 				for position := range containsAdsBlockChildren {
-					if containsAdsBlockChildren[position].NodeValue == valueContainsAds {
+					if locale.matchesLabel(containsAdsBlockChildren[position].NodeValue, selectors.ValueContainsAds) {
 						containsAds = true
 						break
 					}
 				}
 			}
 		} else {
-			containsAdsError = errors.New("containsAds : there is no <div class=\"" + classAppContainsAds + "\"></div> in main information block \"app\"")
+			containsAdsError = errors.New("containsAds : there is no <div class=\"" + selectors.ClassAppContainsAds + "\"></div> in main information block \"app\"")
 		}
 	} else {
 		containsAdsError = informationBlockAppError
@@ -804,27 +1016,27 @@ func getContainsAds(document soup.Root) (bool, error) {
 }
 
 // returns if the app offers purchases
-func getInAppPurchases(document soup.Root) (bool, error) {
+func getInAppPurchases(document soup.Root, selectors SelectorProfile, locale Locale) (bool, error) {
 	inAppPurchases := false
 	var inAppPurchasesError error = nil
 
-	informationBlockApp, informationBlockAppError := getMainInformationBlockApp(document, "inAppPurchases")
+	informationBlockApp, informationBlockAppError := getMainInformationBlockApp(document, selectors, "inAppPurchases")
 	if informationBlockAppError == nil {
-		inAppPurchasesBlock := informationBlockApp.Find(div, class, classAppInAppPurchases)
+		inAppPurchasesBlock := informationBlockApp.Find(div, class, selectors.ClassAppInAppPurchases)
 		if inAppPurchasesBlock.Error == nil {
 			inAppPurchasesBlockChildren := inAppPurchasesBlock.Children(true)
-			if len(inAppPurchasesBlockChildren) == 0 && inAppPurchasesBlock.Text() == valueInAppPurchases {
+			if len(inAppPurchasesBlockChildren) == 0 && locale.matchesLabel(inAppPurchasesBlock.Text(), selectors.ValueInAppPurchases) {
 				inAppPurchases = true
 			} else {
 				for position := range inAppPurchasesBlockChildren {
-					if inAppPurchasesBlockChildren[position].NodeValue == valueInAppPurchases {
+					if locale.matchesLabel(inAppPurchasesBlockChildren[position].NodeValue, selectors.ValueInAppPurchases) {
 						inAppPurchases = true
 						break
 					}
 				}
 			}
 		} else {
-			inAppPurchasesError = errors.New("inAppPurchases : there is no <div class=\"" + classAppInAppPurchases + "\"></div> in main information block \"app\"")
+			inAppPurchasesError = errors.New("inAppPurchases : there is no <div class=\"" + selectors.ClassAppInAppPurchases + "\"></div> in main information block \"app\"")
 		}
 	} else {
 		inAppPurchasesError = informationBlockAppError
@@ -833,38 +1045,31 @@ func getInAppPurchases(document soup.Root) (bool, error) {
 }
 
 // return the date of last update
-func getLastUpdate(document soup.Root) (int64, error) {
-	var lastUpdate int64 = 0
+func getLastUpdate(document soup.Root, selectors SelectorProfile, locale Locale) (*time.Time, error) {
+	var lastUpdate *time.Time
 	var lastUpdateError error = nil
-	childPosition := 0
 
-	informationBlockAdditionalChild, informationBlockAdditionalChildError := getMainInformationBlockAdditionalChild(document, "lastUpdate", childPosition)
-	if informationBlockAdditionalChildError == nil {
-		lastUpdateElements := informationBlockAdditionalChild.FindAll(span)
+	informationBlockAdditionalEntry, informationBlockAdditionalEntryError := getMainInformationBlockAdditionalEntry(document, selectors, locale, "lastUpdate", additionalUpdated)
+	if informationBlockAdditionalEntryError == nil {
+		lastUpdateElements := informationBlockAdditionalEntry.FindAll(span)
 		if len(lastUpdateElements) > 0 {
 			lastUpdateString := lastUpdateElements[len(lastUpdateElements)-1].Text()
 			lastUpdateString = strings.TrimSpace(lastUpdateString)
 			if lastUpdateString != "" {
-				lastUpdateObject, lastUpdateObjectError := time.Parse("January 2, 2006", lastUpdateString)
+				lastUpdateObject, lastUpdateObjectError := locale.parseDate(lastUpdateString)
 				if lastUpdateObjectError == nil {
-					lastUpdateFormatted := strftime.Format("%Y%m%d", lastUpdateObject)
-					lastUpdateNumber, lastUpdateNumberError := strconv.ParseInt(lastUpdateFormatted, 0, 64)
-					if lastUpdateNumberError == nil {
-						lastUpdate = lastUpdateNumber
-					} else {
-						lastUpdateError = errors.New("lastUpdate : content of last span of " + string(childPosition+1) + ". child of <div class=\"" + classMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" couldn't be converted into a number")
-					}
+					lastUpdate = &lastUpdateObject
 				} else {
-					lastUpdateError = errors.New("lastUpdate : content of last span of " + string(childPosition+1) + ". child of <div class=\"" + classMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" doesn't contain a date")
+					lastUpdateError = errors.New("lastUpdate : content of last span of entry \"" + additionalUpdated + "\" of <div class=\"" + selectors.ClassMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" doesn't contain a date")
 				}
 			} else {
-				lastUpdateError = errors.New("lastUpdate : last span of " + string(childPosition+1) + ". child of <div class=\"" + classMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" should contain a date but is empty")
+				lastUpdateError = errors.New("lastUpdate : last span of entry \"" + additionalUpdated + "\" of <div class=\"" + selectors.ClassMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" should contain a date but is empty")
 			}
 		} else {
-			lastUpdateError = errors.New("lastUpdate : " + string(childPosition+1) + ". child of <div class=\"" + classMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" should contain at least one span at lower levels")
+			lastUpdateError = errors.New("lastUpdate : entry \"" + additionalUpdated + "\" of <div class=\"" + selectors.ClassMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" should contain at least one span at lower levels")
 		}
 	} else {
-		lastUpdateError = informationBlockAdditionalChildError
+		lastUpdateError = informationBlockAdditionalEntryError
 	}
 
 	return lastUpdate, lastUpdateError
@@ -876,19 +1081,18 @@ func getOs() string {
 }
 
 // returns the required version of operating system
-func getRequiresOsVersion(document soup.Root) (string, error) {
+func getRequiresOsVersion(document soup.Root, selectors SelectorProfile, locale Locale) (string, error) {
 	requiresOsVersion := ""
 	var requiresOsVersionError error = nil
-	childPosition := 4
 
-	informationBlockAdditionalChild, informationBlockAdditionalChildError := getMainInformationBlockAdditionalChild(document, "requiresOsVersion", childPosition)
-	if informationBlockAdditionalChildError == nil {
-		requiresOsVersionElements := informationBlockAdditionalChild.FindAll(span)
+	informationBlockAdditionalEntry, informationBlockAdditionalEntryError := getMainInformationBlockAdditionalEntry(document, selectors, locale, "requiresOsVersion", additionalRequiresAndroid)
+	if informationBlockAdditionalEntryError == nil {
+		requiresOsVersionElements := informationBlockAdditionalEntry.FindAll(span)
 		if len(requiresOsVersionElements) > 0 {
 			requiresOsVersionString := requiresOsVersionElements[len(requiresOsVersionElements)-1].Text()
 			requiresOsVersionString = strings.TrimSpace(requiresOsVersionString)
 			if requiresOsVersionString != "" {
-				if requiresOsVersionString == valueRequiresOsVersion {
+				if locale.matchesLabel(requiresOsVersionString, selectors.ValueRequiresOsVersion) {
 					requiresOsVersion = requiresOsVersionString
 				} else {
 					osVersionParts := strings.Fields(requiresOsVersionString)
@@ -899,27 +1103,26 @@ func getRequiresOsVersion(document soup.Root) (string, error) {
 					requiresOsVersion = osVersion
 				}
 			} else {
-				requiresOsVersionError = errors.New("requiresOsVersion : last span of " + string(childPosition+1) + ". child of <div class=\"" + classMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" should contain a string but is empty")
+				requiresOsVersionError = errors.New("requiresOsVersion : last span of entry \"" + additionalRequiresAndroid + "\" of <div class=\"" + selectors.ClassMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" should contain a string but is empty")
 			}
 		} else {
-			requiresOsVersionError = errors.New("requiresOsVersion : " + string(childPosition+1) + ". child of <div class=\"" + classMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" should contain at least one span at lower levels")
+			requiresOsVersionError = errors.New("requiresOsVersion : entry \"" + additionalRequiresAndroid + "\" of <div class=\"" + selectors.ClassMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" should contain at least one span at lower levels")
 		}
 	} else {
-		requiresOsVersionError = informationBlockAdditionalChildError
+		requiresOsVersionError = informationBlockAdditionalEntryError
 	}
 
 	return requiresOsVersion, requiresOsVersionError
 }
 
 // returns the current version of the app
-func getCurrentSoftwareVersion(document soup.Root) (string, error) {
+func getCurrentSoftwareVersion(document soup.Root, selectors SelectorProfile, locale Locale) (string, error) {
 	currentSoftwareVersion := ""
 	var currentSoftwareVersionError error = nil
-	childPosition := 3
 
-	informationBlockAdditionalChild, informationBlockAdditionalChildError := getMainInformationBlockAdditionalChild(document, "currentSoftwareVersion", childPosition)
-	if informationBlockAdditionalChildError == nil {
-		currentSoftwareVersionElements := informationBlockAdditionalChild.FindAll(span)
+	informationBlockAdditionalEntry, informationBlockAdditionalEntryError := getMainInformationBlockAdditionalEntry(document, selectors, locale, "currentSoftwareVersion", additionalCurrentVersion)
+	if informationBlockAdditionalEntryError == nil {
+		currentSoftwareVersionElements := informationBlockAdditionalEntry.FindAll(span)
 		if len(currentSoftwareVersionElements) > 0 {
 			requiresOsVersionString := currentSoftwareVersionElements[len(currentSoftwareVersionElements)-1].Text()
 			currentSoftwareVersion = strings.TrimSpace(requiresOsVersionString)
@@ -927,21 +1130,21 @@ func getCurrentSoftwareVersion(document soup.Root) (string, error) {
 				currentSoftwareVersion = valueCurrentSoftwareVersionDefault
 			}
 		} else {
-			currentSoftwareVersionError = errors.New("requiresOsVersion : " + string(childPosition+1) + ". child of <div class=\"" + classMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" should contain at least one span at lower levels")
+			currentSoftwareVersionError = errors.New("requiresOsVersion : entry \"" + additionalCurrentVersion + "\" of <div class=\"" + selectors.ClassMainInformationAdditionalContainer + "\"></div> in main information block \"additional information\" should contain at least one span at lower levels")
 		}
 	} else {
-		currentSoftwareVersionError = informationBlockAdditionalChildError
+		currentSoftwareVersionError = informationBlockAdditionalEntryError
 	}
 
 	return currentSoftwareVersion, currentSoftwareVersionError
 }
 
 // returns
-func getSimilarApps(document soup.Root) ([]string, error) {
+func getSimilarApps(ctx context.Context, fetcher Fetcher, document soup.Root, selectors SelectorProfile) ([]string, error) {
 	var similarApps []string
 	var similarAppsError error = nil
 
-	similarAppElements, similarAppElementsError := getMainInformationBlockSimilarChildren(document, "similarApps")
+	similarAppElements, similarAppElementsError := getMainInformationBlockSimilarChildren(ctx, fetcher, document, selectors, "similarApps")
 	if similarAppElementsError == nil {
 		for position := range similarAppElements {
 			similarAppLink := similarAppElements[position].Find("a")
@@ -975,17 +1178,159 @@ func getSimilarApps(document soup.Root) ([]string, error) {
 	return similarApps, similarAppsError
 }
 
-// returns the current date as integer
-func getCurrentDate() int64 {
-	var currentDate int64 = 0
-	dateNow := time.Now()
-	currentDateFormatted := strftime.Format("%Y%m%d", dateNow)
-	formattedValue, parseError := strconv.Atoi(currentDateFormatted)
+// returns the url of the data safety section of the app in the storefront of the options
+func (options CrawlOptions) dataSafetyURL(packageName string) string {
+	pageURL := baseURL + pathDataSafety + "?id=" + url.QueryEscape(packageName) + "&hl=" + url.QueryEscape(options.Language)
+	if options.Country != "" {
+		pageURL += "&gl=" + url.QueryEscape(options.Country)
+	}
+
+	return pageURL
+}
+
+// returns whether the page links to the data safety section of the app
+func hasDataSafetyLink(document soup.Root) bool {
+	for _, link := range document.FindAll(a) {
+		if linkURL, parseError := url.Parse(link.GetAttribute(href)); parseError == nil && linkURL.Path == pathDataSafety {
+			return true
+		}
+	}
+
+	return false
+}
+
+// returns the data the app shares and collects together with the security practices of the developer from the data
+// safety section
+func getDataSafety(ctx context.Context, fetcher Fetcher, dataSafetyURL string, selectors SelectorProfile, locale Locale) (DataSafety, error) {
+	dataSafety := DataSafety{SharedData: []DataSafetyEntry{}, CollectedData: []DataSafetyEntry{}, Purposes: []string{}}
+
+	dataSafetyPage, dataSafetyPageError := fetcher.Fetch(ctx, dataSafetyURL)
+	if dataSafetyPageError != nil {
+		return dataSafety, errors.New("dataSafety : the data safety section couldn't be requested : " + dataSafetyPageError.Error())
+	}
+	if dataSafetyPage.StatusCode != http.StatusOK {
+		return dataSafety, errors.New("dataSafety : the data safety section answered with status " + strconv.Itoa(dataSafetyPage.StatusCode))
+	}
+	sections := parseDoc(dataSafetyPage.Body).FindAll(div, class, selectors.ClassDataSafetySection)
+	if len(sections) == 0 {
+		return dataSafety, errors.New("dataSafety : there is no <div class=\"" + selectors.ClassDataSafetySection + "\"></div> in the data safety section")
+	}
+
+	for _, section := range sections {
+		headline := section.Find(h2)
+		if headline.Error != nil {
+			continue
+		}
+		part, _ := locale.translateLabel(headline.Text())
+		switch part {
+		case dataSafetyShared:
+			dataSafety.SharedData = getDataSafetyEntries(section, selectors, locale)
+		case dataSafetyCollected:
+			dataSafety.CollectedData = getDataSafetyEntries(section, selectors, locale)
+		case dataSafetySecurityPractices:
+			for _, practice := range section.FindAll(h3) {
+				practicePart, _ := locale.translateLabel(practice.Text())
+				dataSafety.SecurityPractices.EncryptedInTransit = dataSafety.SecurityPractices.EncryptedInTransit || practicePart == dataSafetyEncryptedInTransit
+				dataSafety.SecurityPractices.DeletionRequest = dataSafety.SecurityPractices.DeletionRequest || practicePart == dataSafetyDeletionRequest
+			}
+		}
+	}
+	for _, entry := range append(append([]DataSafetyEntry{}, dataSafety.SharedData...), dataSafety.CollectedData...) {
+		for _, purpose := range entry.Purposes {
+			if !containsString(dataSafety.Purposes, purpose) {
+				dataSafety.Purposes = append(dataSafety.Purposes, purpose)
+			}
+		}
+	}
+
+	return dataSafety, nil
+}
+
+// returns the types of data of all categories of the section, for example "Approximate location" of "Location"
+func getDataSafetyEntries(section soup.Root, selectors SelectorProfile, locale Locale) []DataSafetyEntry {
+	entries := []DataSafetyEntry{}
+
+	for _, category := range section.FindAll(div, class, selectors.ClassDataSafetyCategory) {
+		categoryName := ""
+		if categoryHeadline := category.Find(h3); categoryHeadline.Error == nil {
+			categoryName = strings.TrimSpace(categoryHeadline.Text())
+		}
+		for _, entryElement := range category.FindAll(div, class, selectors.ClassDataSafetyEntry) {
+			typeElement := entryElement.Find(div, class, selectors.ClassDataSafetyType)
+			if typeElement.Error != nil {
+				continue
+			}
+			entry := DataSafetyEntry{Category: categoryName, Type: strings.TrimSpace(typeElement.Text()), Purposes: []string{}}
+			for _, label := range typeElement.FindAll(span) {
+				if part, _ := locale.translateLabel(label.Text()); part == dataSafetyOptional {
+					entry.Optional = true
+				}
+			}
+			if purposesElement := entryElement.Find(div, class, selectors.ClassDataSafetyPurposes); purposesElement.Error == nil {
+				for _, purpose := range strings.Split(purposesElement.Text(), ",") {
+					if purpose = strings.TrimSpace(purpose); purpose != "" {
+						entry.Purposes = append(entry.Purposes, purpose)
+					}
+				}
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// returns the permissions of the app from the rpc the permission dialog of the app page uses
+func getPermissions(ctx context.Context, packageName string, options CrawlOptions) ([]PermissionGroup, error) {
+	permissions := []PermissionGroup{}
+
+	poster, isPoster := options.Fetcher.(Poster)
+	if !isPoster {
+		return permissions, errors.New("permissions : " + errorFetcherCannotPost)
+	}
+	rpcURL := options.batchExecuteURL(permissionRPCID)
+	response, postError := poster.Post(ctx, rpcURL, getBatchExecuteRequest(permissionRPCID, []interface{}{[]interface{}{nil, []interface{}{packageName, 7}, []interface{}{}}}))
+	if postError != nil {
+		return permissions, errors.New("permissions : the permissions couldn't be requested : " + postError.Error())
+	}
+	if response.StatusCode != http.StatusOK {
+		return permissions, errors.New("permissions : the permission rpc answered with status " + strconv.Itoa(response.StatusCode))
+	}
+	data, parseError := parseBatchExecuteResponse(response.Body, permissionRPCID)
 	if parseError != nil {
-		fmt.Println("ERR", parseError)
-		panic(parseError)
+		return permissions, errors.New("permissions : response of the permission rpc could not be read : " + parseError.Error())
 	}
-	currentDate = int64(formattedValue)
 
-	return currentDate
+	// the common permissions come first, the other ones follow in groups of their own
+	for _, groupsPath := range [][]int{{0}, {1}} {
+		groups, _ := getInitDataValue(data, groupsPath).([]interface{})
+		for _, group := range groups {
+			groupList, isList := group.([]interface{})
+			if !isList {
+				continue
+			}
+			permissionGroup := PermissionGroup{Permissions: []string{}}
+			permissionGroup.Group, _ = getInitDataValue(groupList, []int{0}).(string)
+			entries, _ := getInitDataValue(groupList, []int{2}).([]interface{})
+			for _, entry := range entries {
+				entryList, _ := entry.([]interface{})
+				if permission, isString := getInitDataValue(entryList, []int{1}).(string); isString && permission != "" {
+					permissionGroup.Permissions = append(permissionGroup.Permissions, permission)
+				}
+			}
+			permissions = append(permissions, permissionGroup)
+		}
+	}
+
+	return permissions, nil
+}
+
+// returns the current time in UTC, accurate to the second
+func getCrawlTime() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// returns the day of the time in UTC formatted like 20181231
+func getDayNumber(date time.Time) int64 {
+	return getDateNumber(float64(date.Unix()))
 }
@@ -1,11 +1,42 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/OlegSchmidt/soup"
 )
 
+var update = flag.Bool("update", false, "update the golden files in testdata/app-pages")
+var capture = flag.Bool("capture", false, "save the app pages in testdata/app-pages from the Google Play Store")
+
+// app pages in testdata/app-pages and the package name they belong to, the pages are reduced by hand to the parts
+// the crawler reads until they are captured from the Google Play Store with -capture
+var appPageFixtures = []struct {
+	name        string
+	packageName string
+	language    string
+}{
+	{"free", "com.whatsapp", "en"},
+	{"paid", "com.ustwo.monumentvalley", "en"},
+	{"paid-de", "com.ustwo.monumentvalley", "de"},
+	{"ads-iap", "com.king.candycrushsaga", "en"},
+	{"no-ratings", "com.tinyapps.notes", "en"},
+	{"removed", "com.does.not.exists.122", "en"},
+	{"redesigned", "com.spotify.music", "en"},
+}
+
 var mailformedHTML = `
 <html>
   <head>
@@ -23,7 +54,7 @@ func TestCrawlAppPage(t *testing.T) {
 	for _, document := range []string{
 		mailformedHTML,
 	} {
-		appPage := crawlAppPage(soup.HTMLParse(document), "com.test")
+		appPage := crawlAppPage(context.Background(), soup.HTMLParse(document), "com.test", CrawlOptions{}.withDefaults())
 		if appPage.Name != "" {
 			t.Errorf("name should be empty")
 		}
@@ -66,7 +97,7 @@ func TestCrawlAppPage(t *testing.T) {
 		if appPage.InAppPurchases {
 			t.Errorf("InAppPurchases should be false")
 		}
-		if appPage.LastUpdate > 0 {
+		if appPage.LastUpdate != nil {
 			t.Errorf("LastUpdate should be empty")
 		}
 		if appPage.RequiresOsVersion != "" {
@@ -83,3 +114,183 @@ func TestCrawlAppPage(t *testing.T) {
 		}
 	}
 }
+
+// returns the saved english app pages by package name, the removed app is left out so that the store answers with 404
+func loadAppPageFixtures() map[string]string {
+	pages := map[string]string{}
+	for _, fixture := range appPageFixtures {
+		if fixture.name == "removed" || fixture.language != defaultLanguage {
+			continue
+		}
+		html, err := ioutil.ReadFile(filepath.Join("testdata", "app-pages", fixture.name+".html"))
+		if err != nil {
+			panic(err)
+		}
+		pages[fixture.packageName] = string(html)
+	}
+
+	return pages
+}
+
+// returns the data safety section and the response of the permission rpc, all apps share them
+func loadAppDetailFixtures() map[string]string {
+	pages := map[string]string{}
+	for path, name := range map[string]string{
+		pathDataSafety: "datasafety.html",
+		strings.TrimPrefix(baseURLBatchExecute, baseURL): "permissions.txt",
+	} {
+		content, err := ioutil.ReadFile(filepath.Join("testdata", "app-details", name))
+		if err != nil {
+			panic(err)
+		}
+		pages[path] = string(content)
+	}
+
+	return pages
+}
+
+// saves the app page as it is served by the Google Play Store, the page of a removed app is saved with its status 404
+func captureAppPage(t *testing.T, name string, packageName string, language string) {
+	response, err := NewHTTPFetcher().Fetch(context.Background(), CrawlOptions{Language: language}.appPageURL(packageName))
+	if err != nil {
+		t.Fatalf("could not capture app page : %v", err)
+	}
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNotFound {
+		t.Fatalf("could not capture app page, got status %d", response.StatusCode)
+	}
+	if err := ioutil.WriteFile(filepath.Join("testdata", "app-pages", name+".html"), []byte(response.Body), 0644); err != nil {
+		t.Fatalf("could not save app page : %v", err)
+	}
+}
+
+// removes the values which change with every crawl
+func normalizeAppPage(appPage AppPage) AppPage {
+	appPage.DateCrawled = time.Time{}
+
+	return appPage
+}
+
+// returns the names of the json fields which differ between both app pages
+func diffAppPages(t *testing.T, got []byte, want []byte) []string {
+	var gotFields, wantFields map[string]interface{}
+	if err := json.Unmarshal(got, &gotFields); err != nil {
+		t.Fatalf("could not decode crawled app page : %v", err)
+	}
+	if err := json.Unmarshal(want, &wantFields); err != nil {
+		t.Fatalf("could not decode golden app page : %v", err)
+	}
+
+	var fields []string
+	for field := range gotFields {
+		if !reflect.DeepEqual(gotFields[field], wantFields[field]) {
+			fields = append(fields, field)
+		}
+	}
+	for field := range wantFields {
+		if _, found := gotFields[field]; !found {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	return fields
+}
+
+func TestCrawlAppPageGolden(t *testing.T) {
+	// only the details of the apps are behind the fetcher, so the similar apps fall back to the app page itself
+	fetcher := newTestFetcher(loadAppDetailFixtures())
+	defer fetcher.Close()
+
+	for _, fixture := range appPageFixtures {
+		t.Run(fixture.name, func(t *testing.T) {
+			if *capture {
+				captureAppPage(t, fixture.name, fixture.packageName, fixture.language)
+			}
+			document := parseDoc(readFixture(t, "app-pages", fixture.name))
+			appPage := normalizeAppPage(crawlAppPage(context.Background(), document, fixture.packageName, CrawlOptions{Fetcher: fetcher, Language: fixture.language}.withDefaults()))
+			var buffer bytes.Buffer
+			encoder := json.NewEncoder(&buffer)
+			encoder.SetEscapeHTML(false)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(appPage); err != nil {
+				t.Fatalf("could not encode app page : %v", err)
+			}
+			got := buffer.Bytes()
+
+			goldenPath := filepath.Join("testdata", "app-pages", fixture.name+".golden.json")
+			if *update {
+				if err := ioutil.WriteFile(goldenPath, got, 0644); err != nil {
+					t.Fatalf("could not update golden file : %v", err)
+				}
+			}
+			want, err := ioutil.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("could not read golden file, run the tests with -update to create it : %v", err)
+			}
+			for _, field := range diffAppPages(t, got, want) {
+				t.Errorf("field %q differs from %s", field, goldenPath)
+			}
+		})
+	}
+}
+
+func TestCountPerRating(t *testing.T) {
+	page := readFixture(t, "app-pages", "free")
+	locale := getLocale("en")
+	wantCountPerRating := StarCountPerRating{Five: 46516339, Four: 6880243, Three: 3057542, Two: 1345675, One: 3251151}
+
+	// newer pages name the number of ratings in the label of the bars
+	labelled := parseDoc(regexp.MustCompile(`title="([0-9,]+)"`).ReplaceAllString(page, `aria-label="$1 ratings"`))
+	countPerRating, countPerRatingError := getCountPerRating(labelled, defaultSelectorProfile, locale)
+	if countPerRatingError != nil || countPerRating != wantCountPerRating {
+		t.Errorf("count per rating should be %+v, got %+v and %v", wantCountPerRating, countPerRating, countPerRatingError)
+	}
+
+	withoutCounts := parseDoc(regexp.MustCompile(`title="[0-9,]+"`).ReplaceAllString(page, ""))
+	_, countPerRatingError = getCountPerRating(withoutCounts, defaultSelectorProfile, locale)
+	if countPerRatingError == nil {
+		t.Errorf("count per rating should fail without the number of ratings")
+	}
+	percentPerRating, percentPerRatingError := getPercentPerRatingFromWidths(withoutCounts, defaultSelectorProfile)
+	wantPercentPerRating := StarPercentPerRating{Five: 76, Four: 11, Three: 5, Two: 2, One: 6}
+	if percentPerRatingError != nil || percentPerRating != wantPercentPerRating {
+		t.Errorf("percent per rating should be %+v, got %+v and %v", wantPercentPerRating, percentPerRating, percentPerRatingError)
+	}
+
+	// trailing and empty parts of the style are skipped
+	withTrailingSemicolon := parseDoc(regexp.MustCompile(`style="(width: [0-9]+%)"`).ReplaceAllString(regexp.MustCompile(`title="[0-9,]+"`).ReplaceAllString(page, ""), `style="color: red;; $1;"`))
+	percentPerRating, percentPerRatingError = getPercentPerRatingFromWidths(withTrailingSemicolon, defaultSelectorProfile)
+	if percentPerRatingError != nil || percentPerRating != wantPercentPerRating {
+		t.Errorf("percent per rating should be %+v with a trailing \";\", got %+v and %v", wantPercentPerRating, percentPerRating, percentPerRatingError)
+	}
+}
+
+func TestDataSafetyAndPermissions(t *testing.T) {
+	fetcher := newTestFetcher(loadAppDetailFixtures())
+	defer fetcher.Close()
+	options := CrawlOptions{Fetcher: fetcher, Country: "de"}.withDefaults()
+
+	dataSafety, err := getDataSafety(context.Background(), fetcher, options.dataSafetyURL("com.spotify.music"), options.Selectors, getLocale("en"))
+	if err != nil {
+		t.Fatalf("data safety should be extracted, got %v", err)
+	}
+	if len(dataSafety.SharedData) != 2 || len(dataSafety.CollectedData) != 3 || !dataSafety.CollectedData[1].Optional || dataSafety.CollectedData[0].Optional {
+		t.Errorf("unexpected data %+v", dataSafety)
+	}
+	if !dataSafety.SecurityPractices.EncryptedInTransit || !dataSafety.SecurityPractices.DeletionRequest {
+		t.Errorf("security practices should be extracted, got %+v", dataSafety.SecurityPractices)
+	}
+
+	permissions, err := getPermissions(context.Background(), "com.spotify.music", options)
+	if err != nil || len(permissions) != 4 || permissions[3].Group != "Other" || permissions[2].Permissions[0] != "record audio" {
+		t.Errorf("unexpected permissions %+v (%v)", permissions, err)
+	}
+	requested := fetcher.Requested()
+	if len(requested) != 2 || requested[0] != "/store/apps/datasafety?id=com.spotify.music&hl=en&gl=de" || requested[1] != "/_/PlayStoreUi/data/batchexecute?rpcids=xdSrCf&hl=en&gl=de" {
+		t.Errorf("unexpected requests %v", requested)
+	}
+
+	if _, err = getPermissions(context.Background(), "com.spotify.music", CrawlOptions{Fetcher: slowFetcher{fetcher: fetcher}}); err == nil {
+		t.Errorf("fetchers which can't send forms should fail")
+	}
+}
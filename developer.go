@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OlegSchmidt/soup"
+)
+
+const (
+	baseURLDeveloperPage = baseURL + "/store/apps/dev?id="
+	// developers without a numeric id are addressed by their name
+	baseURLDeveloperPageByName = baseURL + "/store/apps/developer?id="
+
+	// paths of the links on the pages of the Google Play Store
+	pathAppPage          = "/store/apps/details"
+	pathDeveloperPage    = "/store/apps/dev"
+	pathDeveloperPageOld = "/store/apps/developer"
+	pathCluster          = "/store/apps/collection/cluster"
+)
+
+// DeveloperPage model
+type DeveloperPage struct {
+	DeveloperID  string    `json:"developer_id" bson:"developer_id"`
+	DateCrawled  time.Time `json:"date_crawled" bson:"date_crawled"`
+	Language     string    `json:"language" bson:"language"`
+	Country      string    `json:"country" bson:"country"`
+	Name         string    `json:"name" bson:"name"`
+	Website      string    `json:"website" bson:"website"`
+	Description  string    `json:"description" bson:"description"`
+	PackageNames []string  `json:"package_names" bson:"package_names"`
+	Errors       []string  `json:"errors" bson:"errors"`
+}
+
+// returns the url of the developer page in the storefront of the options
+func (options CrawlOptions) developerPageURL(developerID string) string {
+	pageURL := baseURLDeveloperPageByName + url.QueryEscape(developerID)
+	if isNumeric(developerID) {
+		pageURL = baseURLDeveloperPage + developerID
+	}
+	pageURL += "&hl=" + url.QueryEscape(options.Language)
+	if options.Country != "" {
+		pageURL += "&gl=" + url.QueryEscape(options.Country)
+	}
+
+	return pageURL
+}
+
+// CrawlDeveloper crawls the page of the developer together with the lists of apps it links to, the returned error
+// wraps the same errors as CrawlContext
+func CrawlDeveloper(ctx context.Context, developerID string, options CrawlOptions) (DeveloperPage, error) {
+	var developerPage DeveloperPage
+	options = options.withDefaults()
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	pageURL := options.developerPageURL(developerID)
+	document, response, retrieveError := retrieveDoc(ctx, options.Fetcher, pageURL)
+	if retrieveError != nil {
+		return developerPage, &CrawlError{Err: getContextError(ctx, ErrUpstreamUnavailable), PackageName: developerID, URL: pageURL, Detail: retrieveError.Error()}
+	}
+	if blockedReason := getBlockedReason(response, options.Selectors); blockedReason != "" {
+		return developerPage, &CrawlError{Err: ErrBlocked, PackageName: developerID, URL: pageURL, StatusCode: response.StatusCode, RetryAfter: response.Header.Get("Retry-After"), Detail: blockedReason}
+	}
+	if response.StatusCode != http.StatusOK {
+		return developerPage, &CrawlError{Err: errorFromStatusCode(response.StatusCode), PackageName: developerID, URL: pageURL, StatusCode: response.StatusCode, RetryAfter: response.Header.Get("Retry-After")}
+	}
+
+	developerPage = crawlDeveloperPage(ctx, document, developerID, options)
+	if ctx.Err() != nil {
+		return developerPage, &CrawlError{Err: getContextError(ctx, ErrTimeout), PackageName: developerID, URL: pageURL, Detail: ctx.Err().Error()}
+	}
+	if developerPage.Name == "" && len(developerPage.PackageNames) == 0 {
+		crawlError := &CrawlError{Err: ErrLayoutChanged, PackageName: developerID, URL: pageURL}
+		if len(developerPage.Errors) > 0 {
+			crawlError.Detail = developerPage.Errors[0]
+		}
+		return developerPage, crawlError
+	}
+
+	return developerPage, nil
+}
+
+// fills the developer page with the values of the document, the errors of the getters are collected in the page
+func crawlDeveloperPage(ctx context.Context, document soup.Root, developerID string, options CrawlOptions) DeveloperPage {
+	var lastError error
+	developerPage := DeveloperPage{DeveloperID: developerID, DateCrawled: getCrawlTime(), Language: options.Language, Country: options.Country}
+	trackError := func(err error) {
+		if err != nil {
+			developerPage.Errors = append(developerPage.Errors, err.Error())
+		}
+	}
+
+	developerPage.Name, lastError = getDeveloperPageName(document, options.Selectors)
+	trackError(lastError)
+
+	developerPage.Description, lastError = getDeveloperPageDescription(document, options.Selectors)
+	trackError(lastError)
+
+	developerPage.Website, lastError = getDeveloperPageWebsite(document, options.Selectors)
+	trackError(lastError)
+
+	var clusterErrors []string
+	developerPage.PackageNames, clusterErrors, lastError = getDeveloperPagePackageNames(ctx, options.Fetcher, document)
+	developerPage.Errors = append(developerPage.Errors, clusterErrors...)
+	trackError(lastError)
+
+	return developerPage
+}
+
+// returns the header of the developer page with the name, the description and the website
+func getDeveloperPageHeader(document soup.Root, selectors SelectorProfile, property string) (soup.Root, error) {
+	header := document.Find(div, class, selectors.ClassDeveloperHeader)
+	if header.Error != nil {
+		return header, errors.New(property + " : there is no <div class=\"" + selectors.ClassDeveloperHeader + "\"></div> on the developer page")
+	}
+
+	return header, nil
+}
+
+// returns the name of the developer
+func getDeveloperPageName(document soup.Root, selectors SelectorProfile) (string, error) {
+	header, headerError := getDeveloperPageHeader(document, selectors, "name")
+	if headerError != nil {
+		return "", headerError
+	}
+	headline := header.Find(h1)
+	if headline.Error != nil {
+		return "", errors.New("name : there is no <h1></h1> inside of <div class=\"" + selectors.ClassDeveloperHeader + "\"></div>")
+	}
+	if headlineSpan := headline.Find(span); headlineSpan.Error == nil {
+		headline = headlineSpan
+	}
+	name := strings.TrimSpace(headline.Text())
+	if name == "" {
+		return "", errors.New("name : <h1></h1> inside of <div class=\"" + selectors.ClassDeveloperHeader + "\"></div> is empty")
+	}
+
+	return name, nil
+}
+
+// returns the description the developer gives of itself, not all developers have one
+func getDeveloperPageDescription(document soup.Root, selectors SelectorProfile) (string, error) {
+	header, headerError := getDeveloperPageHeader(document, selectors, "description")
+	if headerError != nil {
+		return "", headerError
+	}
+	description := header.Find(div, class, selectors.ClassDeveloperDescription)
+	if description.Error != nil {
+		return "", nil
+	}
+
+	return strings.TrimSpace(description.Text()), nil
+}
+
+// returns the website of the developer, the first link of the header leading away from the Google Play Store
+func getDeveloperPageWebsite(document soup.Root, selectors SelectorProfile) (string, error) {
+	header, headerError := getDeveloperPageHeader(document, selectors, "website")
+	if headerError != nil {
+		return "", headerError
+	}
+	for _, link := range header.FindAll(a) {
+		website := link.GetAttribute(href)
+		if strings.HasPrefix(website, "http") && !strings.HasPrefix(website, baseURL) {
+			return website, nil
+		}
+	}
+
+	return "", nil
+}
+
+// returns the package names of all apps of the developer, the page only shows some apps of each list, the complete
+// lists are on the pages behind their "See more" links. Lists which could not be loaded are reported in the returned
+// messages, the package names are incomplete then
+func getDeveloperPagePackageNames(ctx context.Context, fetcher Fetcher, document soup.Root) ([]string, []string, error) {
+	packageNames := []string{}
+	var clusterErrors []string
+	known := map[string]bool{}
+	addPackageNames := func(page soup.Root) {
+		for _, link := range page.FindAll(a) {
+			if packageName, isAppLink := getPackageNameFromLink(link.GetAttribute(href)); isAppLink && !known[packageName] {
+				known[packageName] = true
+				packageNames = append(packageNames, packageName)
+			}
+		}
+	}
+
+	addPackageNames(document)
+	for _, link := range document.FindAll(a) {
+		clusterLink := link.GetAttribute(href)
+		if !strings.HasPrefix(clusterLink, pathCluster) {
+			continue
+		}
+		clusterResponse, clusterError := fetcher.Fetch(ctx, baseURL+clusterLink)
+		if clusterError != nil {
+			clusterErrors = append(clusterErrors, "packageNames : could not load the list \""+baseURL+clusterLink+"\" : "+clusterError.Error())
+			continue
+		}
+		if clusterResponse.StatusCode != http.StatusOK {
+			clusterErrors = append(clusterErrors, "packageNames : could not load the list \""+baseURL+clusterLink+"\" : status code "+strconv.Itoa(clusterResponse.StatusCode))
+			continue
+		}
+		addPackageNames(parseDoc(clusterResponse.Body))
+	}
+	if len(packageNames) == 0 {
+		return packageNames, clusterErrors, errors.New("packageNames : the developer page doesn't link to any app")
+	}
+
+	return packageNames, clusterErrors, nil
+}
+
+// returns the package name of a link to an app page
+func getPackageNameFromLink(link string) (string, bool) {
+	linkURL, parseError := url.Parse(link)
+	if parseError != nil || linkURL.Path != pathAppPage {
+		return "", false
+	}
+	packageName := linkURL.Query().Get("id")
+
+	return packageName, packageName != ""
+}
+
+// returns the developer id of a link to a developer page, either a number or the name of the developer
+func getDeveloperIDFromLink(link string) (string, bool) {
+	linkURL, parseError := url.Parse(link)
+	if parseError != nil || (linkURL.Path != pathDeveloperPage && linkURL.Path != pathDeveloperPageOld) {
+		return "", false
+	}
+	developerID := linkURL.Query().Get("id")
+
+	return developerID, developerID != ""
+}
+
+// returns whether the value only consists of digits
+func isNumeric(value string) bool {
+	for _, character := range value {
+		if character < '0' || character > '9' {
+			return false
+		}
+	}
+
+	return value != ""
+}
+
+// returns the id of the developer from the link to the developer page at the top of the app page
+func getDeveloperID(document soup.Root, selectors SelectorProfile) (string, error) {
+	informationBlockApp, informationBlockAppError := getMainInformationBlockApp(document, selectors, "developerId")
+	if informationBlockAppError != nil {
+		return "", informationBlockAppError
+	}
+	for _, link := range informationBlockApp.FindAll(a) {
+		if developerID, isDeveloperLink := getDeveloperIDFromLink(link.GetAttribute(href)); isDeveloperLink {
+			return developerID, nil
+		}
+	}
+
+	return "", errors.New("developerId : main information block \"app\" doesn't contain a link to the developer page")
+}
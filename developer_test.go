@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCrawlDeveloper(t *testing.T) {
+	fetcher := newTestFetcher(map[string]string{
+		"7254049149491024839": readFixture(t, "developer-pages", "spotify"),
+		pathCluster:           readFixture(t, "developer-pages", "spotify-cluster"),
+	})
+	defer fetcher.Close()
+
+	developerPage, err := CrawlDeveloper(context.Background(), "7254049149491024839", CrawlOptions{Fetcher: fetcher, Country: "de"})
+	if err != nil {
+		t.Fatalf("developer page should be crawled, got %v", err)
+	}
+	if developerPage.Name != "Spotify AB" || developerPage.Website != "https://www.spotify.com/" {
+		t.Errorf("unexpected name %q or website %q", developerPage.Name, developerPage.Website)
+	}
+	if !strings.HasPrefix(developerPage.Description, "Spotify is a digital music service") {
+		t.Errorf("unexpected description %q", developerPage.Description)
+	}
+	wantPackageNames := []string{"com.spotify.music", "com.spotify.lite", "com.spotify.tv.android", "com.anchor.android"}
+	if !reflect.DeepEqual(developerPage.PackageNames, wantPackageNames) {
+		t.Errorf("package names of the page and its lists should be collected once, got %v", developerPage.PackageNames)
+	}
+	if requested := fetcher.Requested(); len(requested) != 2 || requested[0] != "/store/apps/dev?id=7254049149491024839&hl=en&gl=de" {
+		t.Errorf("unexpected requests %v", requested)
+	}
+}
+
+func TestCrawlDeveloperClusterFailed(t *testing.T) {
+	fetcher := newTestFetcher(map[string]string{"7254049149491024839": readFixture(t, "developer-pages", "spotify")})
+	defer fetcher.Close()
+
+	developerPage, err := CrawlDeveloper(context.Background(), "7254049149491024839", CrawlOptions{Fetcher: fetcher})
+	if err != nil {
+		t.Fatalf("developer page should be crawled, got %v", err)
+	}
+	if len(developerPage.PackageNames) == 0 || !containsError(developerPage.Errors, "packageNames : could not load the list") {
+		t.Errorf("list which could not be loaded should be reported, got %v and %v", developerPage.PackageNames, developerPage.Errors)
+	}
+}
+
+func TestCrawlDeveloperNotFound(t *testing.T) {
+	fetcher := newTestFetcher(map[string]string{})
+	defer fetcher.Close()
+
+	_, err := CrawlDeveloper(context.Background(), "Unknown Developer", CrawlOptions{Fetcher: fetcher})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing developer should be reported as not found, got %v", err)
+	}
+	if requested := fetcher.Requested(); len(requested) != 1 || requested[0] != "/store/apps/developer?id=Unknown+Developer&hl=en" {
+		t.Errorf("developers without a numeric id should be requested by name, got %v", requested)
+	}
+}
+
+func TestDeveloperIDFromLink(t *testing.T) {
+	for link, want := range map[string]string{
+		"https://play.google.com/store/apps/dev?id=5700313618786177705": "5700313618786177705",
+		"/store/apps/developer?id=King":                                 "King",
+		"https://play.google.com/store/apps/details?id=com.whatsapp":    "",
+		"http://www.whatsapp.com/":                                      "",
+	} {
+		if got, _ := getDeveloperIDFromLink(link); got != want {
+			t.Errorf("developer id of %q should be %q, got %q", link, want, got)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// errors returned by Crawl, use errors.Is to check for them
+var (
+	ErrNotFound            = errors.New("app page not found")
+	ErrBlocked             = errors.New("request was blocked by the Google Play Store")
+	ErrUpstreamUnavailable = errors.New("Google Play Store is unavailable")
+	ErrLayoutChanged       = errors.New("layout of the app page changed")
+	ErrTimeout             = errors.New("crawling the app page took too long")
+	ErrCanceled            = errors.New("crawling the app page was canceled")
+)
+
+// errors returned when reading the history of app pages
+var (
+	ErrStoreNotConfigured = errors.New("no store is configured, set MONGO_URL to keep the history of app pages")
+	ErrSnapshotNotFound   = errors.New("no snapshot of the app page was stored")
+)
+
+// error codes of the json error response
+const (
+	errorCodeNotFound            = "not_found"
+	errorCodeBlocked             = "blocked"
+	errorCodeUpstreamUnavailable = "upstream_unavailable"
+	errorCodeLayoutChanged       = "layout_changed"
+	errorCodeStoreNotConfigured  = "store_not_configured"
+	errorCodeTimeout             = "timeout"
+	errorCodeCanceled            = "canceled"
+	errorCodeBadRequest          = "bad_request"
+	errorCodeInternal            = "internal_error"
+)
+
+// CrawlError describes why an app page could not be crawled
+type CrawlError struct {
+	Err         error
+	PackageName string
+	URL         string
+	StatusCode  int
+	RetryAfter  string
+	Detail      string
+}
+
+func (crawlError *CrawlError) Error() string {
+	message := crawlError.Err.Error()
+	if crawlError.PackageName != "" {
+		message = crawlError.PackageName + " : " + message
+	}
+	if crawlError.StatusCode != 0 {
+		message += " (status " + strconv.Itoa(crawlError.StatusCode) + ")"
+	}
+	if crawlError.Detail != "" {
+		message += " : " + crawlError.Detail
+	}
+
+	return message
+}
+
+// Unwrap returns one of the sentinel errors
+func (crawlError *CrawlError) Unwrap() error {
+	return crawlError.Err
+}
+
+// returns the sentinel error matching the status code the Google Play Store answered with
+func errorFromStatusCode(statusCode int) error {
+	switch {
+	case statusCode == http.StatusNotFound || statusCode == http.StatusGone:
+		return ErrNotFound
+	case statusCode == http.StatusTooManyRequests || statusCode == http.StatusForbidden:
+		return ErrBlocked
+	default:
+		return ErrUpstreamUnavailable
+	}
+}
+
+// ErrorResponse model, body of all responses which are not successful
+type ErrorResponse struct {
+	Status      int    `json:"status"`
+	Code        string `json:"code"`
+	Message     string `json:"message"`
+	PackageName string `json:"package_name,omitempty"`
+}
+
+// returns the http status and the json body for an error of Crawl
+func getErrorResponse(err error) ErrorResponse {
+	errorResponse := ErrorResponse{Message: err.Error()}
+	var crawlError *CrawlError
+	if errors.As(err, &crawlError) {
+		errorResponse.PackageName = crawlError.PackageName
+	}
+
+	switch {
+	case errors.Is(err, ErrNotFound) || errors.Is(err, ErrSnapshotNotFound):
+		errorResponse.Status = http.StatusNotFound
+		errorResponse.Code = errorCodeNotFound
+	case errors.Is(err, ErrBlocked):
+		errorResponse.Status = http.StatusServiceUnavailable
+		errorResponse.Code = errorCodeBlocked
+	case errors.Is(err, ErrUpstreamUnavailable):
+		errorResponse.Status = http.StatusBadGateway
+		errorResponse.Code = errorCodeUpstreamUnavailable
+	case errors.Is(err, ErrLayoutChanged):
+		errorResponse.Status = http.StatusBadGateway
+		errorResponse.Code = errorCodeLayoutChanged
+	case errors.Is(err, ErrTimeout):
+		errorResponse.Status = http.StatusGatewayTimeout
+		errorResponse.Code = errorCodeTimeout
+	case errors.Is(err, ErrCanceled):
+		// nginx' status for clients closing the connection, nobody is reading the response anyway
+		errorResponse.Status = 499
+		errorResponse.Code = errorCodeCanceled
+	case errors.Is(err, ErrStoreNotConfigured):
+		errorResponse.Status = http.StatusServiceUnavailable
+		errorResponse.Code = errorCodeStoreNotConfigured
+	default:
+		errorResponse.Status = http.StatusInternalServerError
+		errorResponse.Code = errorCodeInternal
+	}
+
+	return errorResponse
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestGetErrorResponse(t *testing.T) {
+	for _, testCase := range []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{&CrawlError{Err: errorFromStatusCode(http.StatusNotFound), PackageName: "com.test"}, http.StatusNotFound, errorCodeNotFound},
+		{&CrawlError{Err: errorFromStatusCode(http.StatusTooManyRequests)}, http.StatusServiceUnavailable, errorCodeBlocked},
+		{&CrawlError{Err: errorFromStatusCode(http.StatusInternalServerError)}, http.StatusBadGateway, errorCodeUpstreamUnavailable},
+		{&CrawlError{Err: ErrLayoutChanged}, http.StatusBadGateway, errorCodeLayoutChanged},
+		{errors.New("unknown"), http.StatusInternalServerError, errorCodeInternal},
+	} {
+		errorResponse := getErrorResponse(testCase.err)
+		if errorResponse.Status != testCase.status || errorResponse.Code != testCase.code {
+			t.Errorf("%v should be answered with %d %s, got %d %s", testCase.err, testCase.status, testCase.code, errorResponse.Status, errorResponse.Code)
+		}
+	}
+
+	errorResponse := getErrorResponse(&CrawlError{Err: ErrNotFound, PackageName: "com.test", StatusCode: http.StatusNotFound})
+	if errorResponse.PackageName != "com.test" || errorResponse.Message != "com.test : app page not found (status 404)" {
+		t.Errorf("unexpected error response %+v", errorResponse)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Fetcher retrieves the content behind an url
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) (FetchResponse, error)
+}
+
+// Poster is implemented by fetchers which can also send forms, the rpc calls of the Google Play Store need it
+type Poster interface {
+	Post(ctx context.Context, url string, form url.Values) (FetchResponse, error)
+}
+
+// FetchResponse model
+type FetchResponse struct {
+	Body       string
+	StatusCode int
+	Header     http.Header
+	URL        string
+}
+
+// HTTPFetcher retrieves pages with a plain http client, it is the default fetcher of the crawler
+type HTTPFetcher struct {
+	Client  *http.Client
+	Headers map[string]string
+}
+
+// NewHTTPFetcher returns a fetcher using the default http client
+func NewHTTPFetcher() HTTPFetcher {
+	return HTTPFetcher{Client: http.DefaultClient}
+}
+
+// Fetch requests the url and returns body, status, headers and final url of the response
+func (fetcher HTTPFetcher) Fetch(ctx context.Context, url string) (FetchResponse, error) {
+	request, requestError := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if requestError != nil {
+		return FetchResponse{}, requestError
+	}
+
+	return fetcher.do(request)
+}
+
+// Post sends the form to the url and returns body, status, headers and final url of the response
+func (fetcher HTTPFetcher) Post(ctx context.Context, url string, form url.Values) (FetchResponse, error) {
+	request, requestError := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(form.Encode()))
+	if requestError != nil {
+		return FetchResponse{}, requestError
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=utf-8")
+
+	return fetcher.do(request)
+}
+
+// sends the request with the headers of the fetcher
+func (fetcher HTTPFetcher) do(request *http.Request) (FetchResponse, error) {
+	var fetchResponse FetchResponse
+
+	for name, value := range fetcher.Headers {
+		request.Header.Set(name, value)
+	}
+
+	client := fetcher.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, responseError := client.Do(request)
+	if responseError != nil {
+		return fetchResponse, responseError
+	}
+	defer response.Body.Close()
+
+	body, bodyError := ioutil.ReadAll(response.Body)
+	if bodyError != nil {
+		return fetchResponse, bodyError
+	}
+	fetchResponse.Body = string(body)
+	fetchResponse.StatusCode = response.StatusCode
+	fetchResponse.Header = response.Header
+	// the url after following all redirects
+	fetchResponse.URL = response.Request.URL.String()
+
+	return fetchResponse, nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testFetcher serves prepared pages from a local test server instead of the Google Play Store
+type testFetcher struct {
+	server    *httptest.Server
+	fetcher   HTTPFetcher
+	mutex     sync.Mutex
+	requested []string
+}
+
+// starts a test server answering with the given pages, the key is either the path or the "id" parameter
+func newTestFetcher(pages map[string]string) *testFetcher {
+	fetcher := &testFetcher{}
+	fetcher.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetcher.mutex.Lock()
+		fetcher.requested = append(fetcher.requested, r.URL.RequestURI())
+		fetcher.mutex.Unlock()
+
+		page, found := pages[r.URL.Path]
+		if !found {
+			page, found = pages[r.URL.Query().Get("id")]
+		}
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(page))
+	}))
+	fetcher.fetcher = HTTPFetcher{Client: fetcher.server.Client()}
+
+	return fetcher
+}
+
+// returns the saved page with the given name from the directory of the fixtures
+func readFixture(t *testing.T, directory string, name string) string {
+	html, err := ioutil.ReadFile(filepath.Join("testdata", directory, name+".html"))
+	if err != nil {
+		t.Fatalf("could not read fixture %s : %v", name, err)
+	}
+
+	return string(html)
+}
+
+// redirects the request from the Google Play Store to the test server
+func (fetcher *testFetcher) Fetch(ctx context.Context, url string) (FetchResponse, error) {
+	return fetcher.fetcher.Fetch(ctx, strings.Replace(url, baseURL, fetcher.server.URL, 1))
+}
+
+// sends the form to the test server instead of the Google Play Store
+func (fetcher *testFetcher) Post(ctx context.Context, url string, form url.Values) (FetchResponse, error) {
+	return fetcher.fetcher.Post(ctx, strings.Replace(url, baseURL, fetcher.server.URL, 1), form)
+}
+
+// returns the request uris the test server received so far
+func (fetcher *testFetcher) Requested() []string {
+	fetcher.mutex.Lock()
+	defer fetcher.mutex.Unlock()
+
+	return append([]string{}, fetcher.requested...)
+}
+
+func (fetcher *testFetcher) Close() {
+	fetcher.server.Close()
+}
+
+func TestHTTPFetcherFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Language") != "en" {
+			t.Errorf("header Accept-Language should be sent")
+		}
+		w.Header().Set("X-Test", "value")
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("body"))
+	}))
+	defer server.Close()
+
+	fetcher := HTTPFetcher{Client: server.Client(), Headers: map[string]string{"Accept-Language": "en"}}
+	response, err := fetcher.Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("fetch should not fail : %v", err)
+	}
+	if response.Body != "body" {
+		t.Errorf("body should be \"body\", got %q", response.Body)
+	}
+	if response.StatusCode != http.StatusTeapot {
+		t.Errorf("status should be %d, got %d", http.StatusTeapot, response.StatusCode)
+	}
+	if response.Header.Get("X-Test") != "value" {
+		t.Errorf("header X-Test should be \"value\", got %q", response.Header.Get("X-Test"))
+	}
+}
+
+func TestCrawlWithFetcher(t *testing.T) {
+	fetcher := newTestFetcher(map[string]string{
+		"com.test": mailformedHTML,
+	})
+	defer fetcher.Close()
+
+	appPage, err := Crawl("com.test", CrawlOptions{Fetcher: fetcher})
+	if appPage.PackageName != "com.test" {
+		t.Errorf("package name should be \"com.test\", got %q", appPage.PackageName)
+	}
+	if len(appPage.Errors) == 0 {
+		t.Errorf("there should be errors")
+	}
+	if !errors.Is(err, ErrLayoutChanged) {
+		t.Errorf("error should be ErrLayoutChanged, got %v", err)
+	}
+
+	appPage, err = Crawl("com.does.not.exists", CrawlOptions{Fetcher: fetcher})
+	if appPage.PackageName != "" {
+		t.Errorf("page of a missing app should be empty")
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("error should be ErrNotFound, got %v", err)
+	}
+
+	requested := fetcher.Requested()
+	if len(requested) != 2 || requested[0] != "/store/apps/details?id=com.test&hl=en" {
+		t.Errorf("unexpected requests %v", requested)
+	}
+}
+
+func TestHTTPFetcherPost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.FormValue("f.req") != "[1]" {
+			t.Errorf("form should be posted, got %s with %q", r.Method, r.FormValue("f.req"))
+		}
+		w.Write([]byte("posted"))
+	}))
+	defer server.Close()
+
+	fetcher := HTTPFetcher{Client: server.Client()}
+	response, err := fetcher.Post(context.Background(), server.URL, url.Values{"f.req": []string{"[1]"}})
+	if err != nil || response.Body != "posted" {
+		t.Errorf("post should succeed, got %q and %v", response.Body, err)
+	}
+}
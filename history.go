@@ -0,0 +1,149 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// fields of the app page which are not compared between snapshots because they change with every crawl
+var snapshotFieldsIgnored = map[string]bool{
+	"date_crawled":  true,
+	"field_sources": true,
+	"errors":        true,
+}
+
+// AppPageHistory model, the tracked values of all stored snapshots of an app page
+type AppPageHistory struct {
+	PackageName string            `json:"package_name"`
+	Language    string            `json:"language"`
+	Country     string            `json:"country"`
+	Snapshots   []AppPageSnapshot `json:"snapshots"`
+}
+
+// AppPageSnapshot model, the values of a single crawl which are expected to change over time
+type AppPageSnapshot struct {
+	DateCrawled             time.Time `json:"date_crawled"`
+	Rating                  float64   `json:"rating"`
+	StarsCount              int64     `json:"stars_count"`
+	EstimatedDownloadNumber int64     `json:"estimated_download_number"`
+	CurrentSoftwareVersion  string    `json:"current_software_version"`
+	WhatsNew                []string  `json:"whats_new"`
+}
+
+// AppPageDiff model, the fields which changed between two snapshots
+type AppPageDiff struct {
+	PackageName string        `json:"package_name"`
+	Language    string        `json:"language"`
+	Country     string        `json:"country"`
+	From        time.Time     `json:"from"`
+	To          time.Time     `json:"to"`
+	Changes     []FieldChange `json:"changes"`
+}
+
+// FieldChange model
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// returns the snapshots of the app page in the storefront of the options, oldest first
+func getAppPageHistory(store Store, packageName string, options CrawlOptions) (AppPageHistory, error) {
+	options = options.withDefaults()
+	history := AppPageHistory{PackageName: packageName, Language: options.Language, Country: options.Country, Snapshots: []AppPageSnapshot{}}
+
+	appPages, appPagesError := getStoredAppPages(store, packageName, options)
+	if appPagesError != nil {
+		return history, appPagesError
+	}
+	for _, appPage := range appPages {
+		history.Snapshots = append(history.Snapshots, AppPageSnapshot{
+			DateCrawled:             appPage.DateCrawled,
+			Rating:                  appPage.Rating,
+			StarsCount:              appPage.StarsCount,
+			EstimatedDownloadNumber: appPage.EstimatedDownloadNumber,
+			CurrentSoftwareVersion:  appPage.CurrentSoftwareVersion,
+			WhatsNew:                appPage.WhatsNew,
+		})
+	}
+
+	return history, nil
+}
+
+// returns the changes between the snapshots crawled on the days "from" and "to", zero selects the latest snapshot for
+// "to" and the snapshot before "to" for "from"
+func getAppPageDiff(store Store, packageName string, options CrawlOptions, from int64, to int64) (AppPageDiff, error) {
+	options = options.withDefaults()
+	diff := AppPageDiff{PackageName: packageName, Language: options.Language, Country: options.Country, Changes: []FieldChange{}}
+
+	appPages, appPagesError := getStoredAppPages(store, packageName, options)
+	if appPagesError != nil {
+		return diff, appPagesError
+	}
+	toPosition := len(appPages) - 1
+	if to != 0 {
+		toPosition = findSnapshot(appPages, to)
+	}
+	fromPosition := toPosition - 1
+	if from != 0 {
+		fromPosition = findSnapshot(appPages, from)
+	}
+	if toPosition < 0 || fromPosition < 0 {
+		return diff, ErrSnapshotNotFound
+	}
+
+	diff.From = appPages[fromPosition].DateCrawled
+	diff.To = appPages[toPosition].DateCrawled
+	diff.Changes = getFieldChanges(appPages[fromPosition], appPages[toPosition])
+
+	return diff, nil
+}
+
+// returns the stored app pages, the error is ErrSnapshotNotFound if there is none
+func getStoredAppPages(store Store, packageName string, options CrawlOptions) ([]AppPage, error) {
+	if store == nil {
+		return nil, ErrStoreNotConfigured
+	}
+	appPages, appPagesError := store.AppPages(packageName, options.Language, options.Country)
+	if appPagesError != nil {
+		return nil, appPagesError
+	}
+	if len(appPages) == 0 {
+		return nil, ErrSnapshotNotFound
+	}
+
+	return appPages, nil
+}
+
+// returns the position of the app page crawled on the day formatted like 20181231, -1 if there is none
+func findSnapshot(appPages []AppPage, dayCrawled int64) int {
+	for position, appPage := range appPages {
+		if getDayNumber(appPage.DateCrawled) == dayCrawled {
+			return position
+		}
+	}
+
+	return -1
+}
+
+// returns the fields which differ between both app pages, named like their json keys
+func getFieldChanges(from AppPage, to AppPage) []FieldChange {
+	changes := []FieldChange{}
+	fromValue := reflect.ValueOf(from)
+	toValue := reflect.ValueOf(to)
+	appPageType := fromValue.Type()
+	for position := 0; position < appPageType.NumField(); position++ {
+		field := strings.Split(appPageType.Field(position).Tag.Get("json"), ",")[0]
+		if snapshotFieldsIgnored[field] {
+			continue
+		}
+		fromField := fromValue.Field(position).Interface()
+		toField := toValue.Field(position).Interface()
+		if !reflect.DeepEqual(fromField, toField) {
+			changes = append(changes, FieldChange{Field: field, From: fromField, To: toField})
+		}
+	}
+
+	return changes
+}
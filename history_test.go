@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// returns a store with three daily snapshots of an app
+func newHistoryStore() *MemoryStore {
+	store := NewMemoryStore()
+	store.SaveAppPage(AppPage{PackageName: "com.whatsapp", Language: "en", DateCrawled: time.Date(2019, 1, 1, 10, 30, 0, 0, time.UTC), Rating: 4.3, StarsCount: 100, CurrentSoftwareVersion: "2.18.1", WhatsNew: []string{"Bug fixes."}})
+	store.SaveAppPage(AppPage{PackageName: "com.whatsapp", Language: "en", DateCrawled: time.Date(2019, 1, 2, 10, 30, 0, 0, time.UTC), Rating: 4.3, StarsCount: 120, CurrentSoftwareVersion: "2.18.1", WhatsNew: []string{"Bug fixes."}})
+	store.SaveAppPage(AppPage{PackageName: "com.whatsapp", Language: "en", DateCrawled: time.Date(2019, 1, 3, 10, 30, 0, 0, time.UTC), Rating: 4.4, StarsCount: 150, CurrentSoftwareVersion: "2.18.2", WhatsNew: []string{"Stickers."}})
+
+	return store
+}
+
+func TestGetAppPageDiff(t *testing.T) {
+	store := newHistoryStore()
+
+	diff, err := getAppPageDiff(store, "com.whatsapp", CrawlOptions{}, 0, 0)
+	if err != nil {
+		t.Fatalf("diff should succeed : %v", err)
+	}
+	if getDayNumber(diff.From) != 20190102 || getDayNumber(diff.To) != 20190103 {
+		t.Errorf("diff should compare the latest two snapshots, got %v and %v", diff.From, diff.To)
+	}
+	var fields []string
+	for _, change := range diff.Changes {
+		fields = append(fields, change.Field)
+	}
+	wantFields := []string{"whats_new", "rating", "stars_count", "current_software_version"}
+	if !reflect.DeepEqual(fields, wantFields) {
+		t.Errorf("changed fields should be %v, got %v", wantFields, fields)
+	}
+
+	diff, _ = getAppPageDiff(store, "com.whatsapp", CrawlOptions{}, 20190101, 20190102)
+	if len(diff.Changes) != 1 || diff.Changes[0].Field != "stars_count" || diff.Changes[0].From != int64(100) || diff.Changes[0].To != int64(120) {
+		t.Errorf("only the stars count should have changed, got %+v", diff.Changes)
+	}
+
+	if _, err := getAppPageDiff(store, "com.whatsapp", CrawlOptions{}, 20181231, 0); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("unknown snapshot should not be found, got %v", err)
+	}
+	if _, err := getAppPageDiff(store, "com.whatsapp", CrawlOptions{Language: "de"}, 0, 0); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("snapshots of another language should not be found, got %v", err)
+	}
+	if _, err := getAppPageHistory(nil, "com.whatsapp", CrawlOptions{}); !errors.Is(err, ErrStoreNotConfigured) {
+		t.Errorf("history without store should fail, got %v", err)
+	}
+}
+
+func TestGetAppPageHistoryHandler(t *testing.T) {
+	appPageStore = newHistoryStore()
+	defer func() { appPageStore = nil }()
+
+	rr := executeRequest(buildRequest("GET", "/hitec/app-page/google-play/com.whatsapp/history", nil, t))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("history should be found, got status %d", rr.Code)
+	}
+	var history AppPageHistory
+	json.NewDecoder(rr.Body).Decode(&history)
+	if len(history.Snapshots) != 3 || history.Snapshots[2].StarsCount != 150 {
+		t.Errorf("history should contain 3 snapshots, got %+v", history.Snapshots)
+	}
+
+	rr = executeRequest(buildRequest("GET", "/hitec/app-page/google-play/com.whatsapp/diff?from=20190101&to=20190103", nil, t))
+	var diff AppPageDiff
+	json.NewDecoder(rr.Body).Decode(&diff)
+	if rr.Code != http.StatusOK || len(diff.Changes) != 4 {
+		t.Errorf("diff should contain 4 changes, got status %d and %+v", rr.Code, diff.Changes)
+	}
+
+	rr = executeRequest(buildRequest("GET", "/hitec/app-page/google-play/com.whatsapp/diff?from=yesterday", nil, t))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("invalid date should be rejected, got status %d", rr.Code)
+	}
+
+	rr = executeRequest(buildRequest("GET", "/hitec/app-page/google-play/com.unknown/history", nil, t))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("unknown app should not be found, got status %d", rr.Code)
+	}
+}
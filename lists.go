@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/OlegSchmidt/soup"
+)
+
+const (
+	baseURLSearch = baseURL + "/store/search?c=apps&q="
+	baseURLApps   = baseURL + "/store/apps"
+
+	// types of app lists
+	AppListTypeSearch = "search"
+	AppListTypeChart  = "chart"
+
+	// category of the charts over all apps
+	ChartCategoryAll = "all"
+)
+
+// collections of the top charts of the Google Play Store
+var chartCollections = map[string]bool{
+	"topselling_free":     true,
+	"topselling_paid":     true,
+	"topgrossing":         true,
+	"movers_shakers":      true,
+	"topselling_new_free": true,
+	"topselling_new_paid": true,
+}
+
+// categories look like GAME_PUZZLE or FAMILY
+var chartCategoryPattern = regexp.MustCompile(`^[A-Z][A-Z_]*$`)
+
+// first number of a text like "Rated 4.4 stars out of five stars"
+var listRatingPattern = regexp.MustCompile(`[0-9]+([.,][0-9]+)?`)
+
+// AppList model, a ranked list of apps found by a search or listed in a top chart
+type AppList struct {
+	Type        string         `json:"type" bson:"type"`
+	Query       string         `json:"query" bson:"query"`
+	Collection  string         `json:"collection" bson:"collection"`
+	Category    string         `json:"category" bson:"category"`
+	DateCrawled time.Time      `json:"date_crawled" bson:"date_crawled"`
+	Language    string         `json:"language" bson:"language"`
+	Country     string         `json:"country" bson:"country"`
+	Entries     []AppListEntry `json:"entries" bson:"entries"`
+	Errors      []string       `json:"errors" bson:"errors"`
+}
+
+// AppListEntry model, an app of a list with the information shown on its card
+type AppListEntry struct {
+	Rank        int     `json:"rank" bson:"rank"`
+	PackageName string  `json:"package_name" bson:"package_name"`
+	Name        string  `json:"name" bson:"name"`
+	Developer   string  `json:"developer" bson:"developer"`
+	DeveloperID string  `json:"developer_id" bson:"developer_id"`
+	Rating      float64 `json:"rating" bson:"rating"`
+	Price       string  `json:"price" bson:"price"`
+}
+
+// returns whether the collection and the category name an existing top chart
+func isValidChart(collection string, category string) bool {
+	return chartCollections[collection] && (category == ChartCategoryAll || chartCategoryPattern.MatchString(category))
+}
+
+// returns the url of the search results in the storefront of the options
+func (options CrawlOptions) searchURL(query string) string {
+	pageURL := baseURLSearch + url.QueryEscape(query) + "&hl=" + url.QueryEscape(options.Language)
+	if options.Country != "" {
+		pageURL += "&gl=" + url.QueryEscape(options.Country)
+	}
+
+	return pageURL
+}
+
+// returns the url of the top chart in the storefront of the options
+func (options CrawlOptions) chartURL(collection string, category string) string {
+	pageURL := baseURLApps
+	if category != ChartCategoryAll {
+		pageURL += "/category/" + url.PathEscape(category)
+	}
+	pageURL += "/collection/" + url.PathEscape(collection) + "?hl=" + url.QueryEscape(options.Language)
+	if options.Country != "" {
+		pageURL += "&gl=" + url.QueryEscape(options.Country)
+	}
+
+	return pageURL
+}
+
+// CrawlSearch crawls the apps the Google Play Store finds for the query, in the order they are shown, the returned
+// error wraps the same errors as CrawlContext
+func CrawlSearch(ctx context.Context, query string, options CrawlOptions) (AppList, error) {
+	options = options.withDefaults()
+	appList := AppList{Type: AppListTypeSearch, Query: query}
+
+	return crawlAppList(ctx, appList, query, options.searchURL(query), options)
+}
+
+// CrawlChart crawls the apps of the top chart, the category ChartCategoryAll returns the chart over all categories,
+// the returned error wraps the same errors as CrawlContext
+func CrawlChart(ctx context.Context, collection string, category string, options CrawlOptions) (AppList, error) {
+	options = options.withDefaults()
+	appList := AppList{Type: AppListTypeChart, Collection: collection, Category: category}
+
+	return crawlAppList(ctx, appList, collection+"/"+category, options.chartURL(collection, category), options)
+}
+
+// crawls the list page, the name identifies the list in the errors like the package name does for app pages
+func crawlAppList(ctx context.Context, appList AppList, name string, pageURL string, options CrawlOptions) (AppList, error) {
+	appList.DateCrawled = getCrawlTime()
+	appList.Language = options.Language
+	appList.Country = options.Country
+	appList.Entries = []AppListEntry{}
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	document, response, retrieveError := retrieveDoc(ctx, options.Fetcher, pageURL)
+	if retrieveError != nil {
+		return appList, &CrawlError{Err: getContextError(ctx, ErrUpstreamUnavailable), PackageName: name, URL: pageURL, Detail: retrieveError.Error()}
+	}
+	if blockedReason := getBlockedReason(response, options.Selectors); blockedReason != "" {
+		return appList, &CrawlError{Err: ErrBlocked, PackageName: name, URL: pageURL, StatusCode: response.StatusCode, RetryAfter: response.Header.Get("Retry-After"), Detail: blockedReason}
+	}
+	if response.StatusCode != http.StatusOK {
+		return appList, &CrawlError{Err: errorFromStatusCode(response.StatusCode), PackageName: name, URL: pageURL, StatusCode: response.StatusCode, RetryAfter: response.Header.Get("Retry-After")}
+	}
+
+	entries, entriesError := getAppListEntries(document, options.Selectors, getLocale(options.Language))
+	if entriesError != nil {
+		return appList, &CrawlError{Err: ErrLayoutChanged, PackageName: name, URL: pageURL, Detail: entriesError.Error()}
+	}
+	appList.Entries = entries
+	for _, entry := range entries {
+		if entry.Name == "" {
+			appList.Errors = append(appList.Errors, "entries : the card of \""+entry.PackageName+"\" doesn't contain <div class=\""+options.Selectors.ClassListCardName+"\"></div>")
+		}
+	}
+
+	return appList, nil
+}
+
+// returns the apps of the cards on the page in the order they are shown, every app is listed once, a page without
+// cards is only valid if it still has the usual page content, otherwise the layout changed
+func getAppListEntries(document soup.Root, selectors SelectorProfile, locale Locale) ([]AppListEntry, error) {
+	entries := []AppListEntry{}
+	known := map[string]bool{}
+
+	for _, card := range document.FindAll(div, class, selectors.ClassListCard) {
+		entry, isAppCard := getAppListEntry(card, selectors, locale)
+		if !isAppCard || known[entry.PackageName] {
+			continue
+		}
+		known[entry.PackageName] = true
+		entry.Rank = len(entries) + 1
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		if _, pageError := getPageDocument(document, selectors); pageError != nil {
+			return entries, errors.New("entries : there is neither a <div class=\"" + selectors.ClassListCard + "\"></div> nor the page content")
+		}
+	}
+
+	return entries, nil
+}
+
+// returns the app of the card and whether the card links to an app
+func getAppListEntry(card soup.Root, selectors SelectorProfile, locale Locale) (AppListEntry, bool) {
+	var entry AppListEntry
+	for _, link := range card.FindAll(a) {
+		linkTarget := link.GetAttribute(href)
+		if packageName, isAppLink := getPackageNameFromLink(linkTarget); isAppLink && entry.PackageName == "" {
+			entry.PackageName = packageName
+		} else if developerID, isDeveloperLink := getDeveloperIDFromLink(linkTarget); isDeveloperLink && entry.DeveloperID == "" {
+			entry.DeveloperID = developerID
+		}
+	}
+	if entry.PackageName == "" {
+		return entry, false
+	}
+
+	if name := card.Find(div, class, selectors.ClassListCardName); name.Error == nil {
+		entry.Name = strings.TrimSpace(name.Text())
+		if entry.Name == "" {
+			entry.Name = name.GetAttribute(title)
+		}
+	}
+	// the class is used both for the container of the developer link and the name inside of it
+	if developers := card.FindAll(div, class, selectors.ClassListCardDeveloper); len(developers) > 0 {
+		entry.Developer = strings.TrimSpace(developers[len(developers)-1].Text())
+	}
+	for _, ratingElement := range card.FindAll(div, "role", "img") {
+		if ratingString := listRatingPattern.FindString(ratingElement.GetAttribute(ariaLabel)); ratingString != "" {
+			entry.Rating, _ = locale.parseDecimal(ratingString)
+			break
+		}
+	}
+	if price := card.Find(span, class, selectors.ClassListCardPrice); price.Error == nil {
+		priceSpans := price.FindAll(span)
+		priceText := price.Text()
+		if len(priceSpans) > 0 {
+			priceText = priceSpans[len(priceSpans)-1].Text()
+		}
+		entry.Price = strings.TrimSpace(priceText)
+	}
+
+	return entry, true
+}
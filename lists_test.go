@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestCrawlSearch(t *testing.T) {
+	fetcher := newTestFetcher(map[string]string{"/store/search": readFixture(t, "lists", "search")})
+	defer fetcher.Close()
+
+	appList, err := CrawlSearch(context.Background(), "messenger app", CrawlOptions{Fetcher: fetcher, Country: "us"})
+	if err != nil {
+		t.Fatalf("search should be crawled, got %v", err)
+	}
+	if requested := fetcher.Requested(); len(requested) != 1 || requested[0] != "/store/search?c=apps&q=messenger+app&hl=en&gl=us" {
+		t.Errorf("unexpected requests %v", requested)
+	}
+
+	var packageNames []string
+	for position, entry := range appList.Entries {
+		if entry.Rank != position+1 {
+			t.Errorf("%s should have rank %d, got %d", entry.PackageName, position+1, entry.Rank)
+		}
+		packageNames = append(packageNames, entry.PackageName)
+	}
+	wantPackageNames := []string{"com.whatsapp", "com.facebook.orca", "org.telegram.messenger", "com.viber.voip"}
+	if !reflect.DeepEqual(packageNames, wantPackageNames) {
+		t.Errorf("only apps should be listed once, got %v", packageNames)
+	}
+	wantEntry := AppListEntry{Rank: 1, PackageName: "com.whatsapp", Name: "WhatsApp Messenger", Developer: "WhatsApp Inc.", DeveloperID: "5700313618786177705", Rating: 4.4}
+	if appList.Entries[0] != wantEntry {
+		t.Errorf("unexpected entry %+v", appList.Entries[0])
+	}
+	if appList.Entries[2].DeveloperID != "Telegram FZ-LLC" || appList.Entries[3].Rating != 0 {
+		t.Errorf("unexpected entries %+v", appList.Entries)
+	}
+	if appList.Type != AppListTypeSearch || appList.Query != "messenger app" || len(appList.Errors) != 0 {
+		t.Errorf("unexpected list %+v", appList)
+	}
+}
+
+func TestCrawlChart(t *testing.T) {
+	fetcher := newTestFetcher(map[string]string{
+		"/store/apps/category/GAME_PUZZLE/collection/topselling_paid": readFixture(t, "lists", "chart"),
+		"/store/apps/collection/topselling_paid":                      readFixture(t, "lists", "empty"),
+	})
+	defer fetcher.Close()
+
+	appList, err := CrawlChart(context.Background(), "topselling_paid", "GAME_PUZZLE", CrawlOptions{Fetcher: fetcher, Language: "de"})
+	if err != nil {
+		t.Fatalf("chart should be crawled, got %v", err)
+	}
+	if len(appList.Entries) != 3 || appList.Entries[1].PackageName != "com.ustwo.monumentvalley2" || appList.Entries[1].Price != "$4.99" || appList.Entries[1].Rating != 4.8 {
+		t.Errorf("unexpected entries %+v", appList.Entries)
+	}
+
+	appList, err = CrawlChart(context.Background(), "topselling_paid", ChartCategoryAll, CrawlOptions{Fetcher: fetcher})
+	if err != nil || len(appList.Entries) != 0 {
+		t.Errorf("empty chart should be crawled without entries, got %+v (%v)", appList, err)
+	}
+	if requested := fetcher.Requested(); requested[len(requested)-1] != "/store/apps/collection/topselling_paid?hl=en" {
+		t.Errorf("chart over all categories should be requested without category, got %v", requested)
+	}
+}
+
+func TestCrawlAppListLayoutChanged(t *testing.T) {
+	fetcher := newTestFetcher(map[string]string{"/store/search": "<html><body><div>redesigned</div></body></html>"})
+	defer fetcher.Close()
+
+	if _, err := CrawlSearch(context.Background(), "messenger", CrawlOptions{Fetcher: fetcher}); !errors.Is(err, ErrLayoutChanged) {
+		t.Errorf("page without cards and content should be reported as changed layout, got %v", err)
+	}
+}
+
+func TestGetAppListsBadRequest(t *testing.T) {
+	for _, endpoint := range []string{
+		"/hitec/crawl/search/google-play",
+		"/hitec/crawl/search/google-play?q=messenger&hl=english",
+		"/hitec/crawl/charts/google-play/topselling_cheap/GAME",
+		"/hitec/crawl/charts/google-play/topselling_free/game",
+	} {
+		rr := executeRequest(buildRequest("GET", endpoint, nil, t))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s should be rejected, got %d", endpoint, rr.Code)
+		}
+	}
+}
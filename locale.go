@@ -0,0 +1,363 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	defaultLanguage = "en"
+)
+
+// validates the values of the "hl" and "gl" parameters, for example "en", "pt_BR" or "de-AT"
+var localeIdentifierPattern = regexp.MustCompile(`^[a-zA-Z]{2,3}([_-][a-zA-Z]{2,4})?$`)
+
+// Locale model, describes how the Google Play Store formats dates and numbers in a language
+type Locale struct {
+	Language         string
+	DecimalSeparator string
+	DateLayouts      []string
+	Months           [12]string
+	MonthsShort      [12]string
+	// labels of the additional information block, the data safety section and the badges in lower case, translated
+	// into the entries of additional.go, the parts of the data safety section and the badges
+	Labels map[string]string
+}
+
+// formats of the languages which are supported when parsing dates and numbers
+var locales = map[string]Locale{
+	"en": {
+		Language:         "en",
+		DecimalSeparator: ".",
+		DateLayouts:      []string{"January 2, 2006", "Jan 2, 2006", "2 January 2006", "2 Jan 2006"},
+		Labels: map[string]string{
+			"updated":                              additionalUpdated,
+			"released":                             additionalReleased,
+			"released on":                          additionalReleased,
+			"size":                                 additionalSize,
+			"installs":                             additionalInstalls,
+			"current version":                      additionalCurrentVersion,
+			"requires android":                     additionalRequiresAndroid,
+			"content rating":                       additionalContentRating,
+			"interactive elements":                 additionalInteractiveElements,
+			"in-app products":                      additionalInAppProducts,
+			"permissions":                          additionalPermissions,
+			"report":                               additionalReport,
+			"offered by":                           additionalOfferedBy,
+			"developer":                            additionalDeveloper,
+			"privacy policy":                       additionalPrivacyPolicy,
+			"data shared":                          dataSafetyShared,
+			"data collected":                       dataSafetyCollected,
+			"security practices":                   dataSafetySecurityPractices,
+			"data is encrypted in transit":         dataSafetyEncryptedInTransit,
+			"you can request that data be deleted": dataSafetyDeletionRequest,
+			"optional":                             dataSafetyOptional,
+			"contains ads":                         badgeContainsAds,
+			"offers in-app purchases":              badgeInAppPurchases,
+			"in-app purchases":                     badgeInAppPurchases,
+			"varies with device":                   valueVariesWithDevice,
+		},
+	},
+	"de": {
+		Language:         "de",
+		DecimalSeparator: ",",
+		DateLayouts:      []string{"2. January 2006", "2. Jan 2006", "02.01.2006"},
+		Months:           [12]string{"januar", "februar", "märz", "april", "mai", "juni", "juli", "august", "september", "oktober", "november", "dezember"},
+		MonthsShort:      [12]string{"jan.", "feb.", "märz", "apr.", "mai", "juni", "juli", "aug.", "sept.", "okt.", "nov.", "dez."},
+		Labels: map[string]string{
+			"aktualisiert":                  additionalUpdated,
+			"veröffentlicht am":             additionalReleased,
+			"größe":                         additionalSize,
+			"installationen":                additionalInstalls,
+			"aktuelle version":              additionalCurrentVersion,
+			"erforderliche android-version": additionalRequiresAndroid,
+			"altersfreigabe":                additionalContentRating,
+			"interaktive elemente":          additionalInteractiveElements,
+			"in-app-produkte":               additionalInAppProducts,
+			"berechtigungen":                additionalPermissions,
+			"melden":                        additionalReport,
+			"angeboten von":                 additionalOfferedBy,
+			"entwickler":                    additionalDeveloper,
+			"datenschutzerklärung":          additionalPrivacyPolicy,
+			"geteilte daten":                dataSafetyShared,
+			"erhobene daten":                dataSafetyCollected,
+			"sicherheitspraktiken":          dataSafetySecurityPractices,
+			"daten werden bei der übertragung verschlüsselt": dataSafetyEncryptedInTransit,
+			"du kannst das löschen von daten beantragen":     dataSafetyDeletionRequest,
+			"enthält werbung":        badgeContainsAds,
+			"bietet in-app-käufe":    badgeInAppPurchases,
+			"in-app-käufe":           badgeInAppPurchases,
+			"variiert je nach gerät": valueVariesWithDevice,
+		},
+	},
+	"fr": {
+		Language:         "fr",
+		DecimalSeparator: ",",
+		DateLayouts:      []string{"2 January 2006", "2 Jan 2006"},
+		Months:           [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		MonthsShort:      [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		Labels: map[string]string{
+			"mise à jour":                       additionalUpdated,
+			"date de sortie":                    additionalReleased,
+			"taille":                            additionalSize,
+			"installations":                     additionalInstalls,
+			"version actuelle":                  additionalCurrentVersion,
+			"nécessite android":                 additionalRequiresAndroid,
+			"classification du contenu":         additionalContentRating,
+			"éléments interactifs":              additionalInteractiveElements,
+			"produits intégrés à l'application": additionalInAppProducts,
+			"autorisations":                     additionalPermissions,
+			"signaler":                          additionalReport,
+			"proposée par":                      additionalOfferedBy,
+			"développeur":                       additionalDeveloper,
+			"règles de confidentialité":         additionalPrivacyPolicy,
+			"données partagées":                 dataSafetyShared,
+			"données collectées":                dataSafetyCollected,
+			"pratiques de sécurité":             dataSafetySecurityPractices,
+			"les données sont chiffrées lors de leur transfert": dataSafetyEncryptedInTransit,
+			"vous pouvez demander la suppression des données":   dataSafetyDeletionRequest,
+			"facultatif":                   dataSafetyOptional,
+			"contient des annonces":        badgeContainsAds,
+			"achats via l'application":     badgeInAppPurchases,
+			"achats intégrés":              badgeInAppPurchases,
+			"variable selon les appareils": valueVariesWithDevice,
+		},
+	},
+	"es": {
+		Language:         "es",
+		DecimalSeparator: ",",
+		DateLayouts:      []string{"2 de January de 2006", "2 Jan 2006"},
+		Months:           [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		MonthsShort:      [12]string{"ene.", "feb.", "mar.", "abr.", "may.", "jun.", "jul.", "ago.", "sept.", "oct.", "nov.", "dic."},
+		Labels: map[string]string{
+			"actualizada":                                additionalUpdated,
+			"fecha de lanzamiento":                       additionalReleased,
+			"tamaño":                                     additionalSize,
+			"descargas":                                  additionalInstalls,
+			"versión actual":                             additionalCurrentVersion,
+			"requiere android":                           additionalRequiresAndroid,
+			"clasificación de contenido":                 additionalContentRating,
+			"elementos interactivos":                     additionalInteractiveElements,
+			"productos de compra en aplicaciones":        additionalInAppProducts,
+			"permisos":                                   additionalPermissions,
+			"denunciar":                                  additionalReport,
+			"ofrecida por":                               additionalOfferedBy,
+			"desarrollador":                              additionalDeveloper,
+			"política de privacidad":                     additionalPrivacyPolicy,
+			"datos compartidos":                          dataSafetyShared,
+			"datos recogidos":                            dataSafetyCollected,
+			"prácticas de seguridad":                     dataSafetySecurityPractices,
+			"los datos se cifran en tránsito":            dataSafetyEncryptedInTransit,
+			"puedes solicitar que se eliminen los datos": dataSafetyDeletionRequest,
+			"opcional":                                   dataSafetyOptional,
+			"contiene anuncios":                          badgeContainsAds,
+			"ofrece compras en aplicaciones":             badgeInAppPurchases,
+			"compras en aplicaciones":                    badgeInAppPurchases,
+			"varía según el dispositivo":                 valueVariesWithDevice,
+		},
+	},
+	"it": {
+		Language:         "it",
+		DecimalSeparator: ",",
+		DateLayouts:      []string{"2 January 2006", "2 Jan 2006"},
+		Months:           [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		MonthsShort:      [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+	},
+	"nl": {
+		Language:         "nl",
+		DecimalSeparator: ",",
+		DateLayouts:      []string{"2 January 2006", "2 Jan 2006"},
+		Months:           [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		MonthsShort:      [12]string{"jan.", "feb.", "mrt.", "apr.", "mei", "jun.", "jul.", "aug.", "sep.", "okt.", "nov.", "dec."},
+	},
+	"pt": {
+		Language:         "pt",
+		DecimalSeparator: ",",
+		DateLayouts:      []string{"2 de January de 2006", "2 de Jan de 2006"},
+		Months:           [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		MonthsShort:      [12]string{"jan.", "fev.", "mar.", "abr.", "mai.", "jun.", "jul.", "ago.", "set.", "out.", "nov.", "dez."},
+	},
+	"ja": {
+		Language:         "ja",
+		DecimalSeparator: ".",
+		DateLayouts:      []string{"2006年1月2日", "2006/01/02"},
+	},
+}
+
+// abbreviations of large numbers as the Google Play Store uses them for installs, e.g. in "1B+" or "10 Mio.+"
+var numberAbbreviations = map[string]float64{
+	"k":   1e3,
+	"tsd": 1e3,
+	"mil": 1e3,
+	"m":   1e6,
+	"mi":  1e6,
+	"mio": 1e6,
+	"mln": 1e6,
+	"b":   1e9,
+	"bn":  1e9,
+	"md":  1e9,
+	"mrd": 1e9,
+	"mld": 1e9,
+	"万":   1e4,
+	"億":   1e8,
+}
+
+// returns the formats for the given language (for example "de" or "pt_BR"), unknown languages fall back to english
+func getLocale(language string) Locale {
+	language = strings.ToLower(language)
+	if position := strings.IndexAny(language, "_-"); position >= 0 {
+		language = language[:position]
+	}
+	locale, found := locales[language]
+	if !found {
+		locale = locales[defaultLanguage]
+	}
+
+	return locale
+}
+
+// returns the entry of the additional information block or the part of the data safety section the label stands for,
+// english labels are understood for every language because the Google Play Store doesn't translate all of them
+func (locale Locale) translateLabel(label string) (string, bool) {
+	label = strings.ToLower(strings.Join(strings.Fields(label), " "))
+	entry, known := locale.Labels[label]
+	if !known {
+		entry, known = locales[defaultLanguage].Labels[label]
+	}
+
+	return entry, known
+}
+
+// returns if the text of the page means the value of the selector profile, e.g. "Enthält Werbung" means "Contains Ads",
+// values without a translation only match the same text
+func (locale Locale) matchesLabel(text string, value string) bool {
+	if strings.TrimSpace(text) == value {
+		return true
+	}
+	entry, known := locale.translateLabel(text)
+	valueEntry, valueKnown := locale.translateLabel(value)
+
+	return known && valueKnown && entry == valueEntry
+}
+
+// parses a date like "27. Oktober 2017" written in the language of the locale
+func (locale Locale) parseDate(value string) (time.Time, error) {
+	value = strings.Join(strings.Fields(strings.ToLower(value)), " ")
+	value = locale.translateMonth(value)
+	for _, layout := range locale.DateLayouts {
+		date, dateError := time.Parse(layout, value)
+		if dateError == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, errors.New("\"" + value + "\" doesn't match any date format of language \"" + locale.Language + "\"")
+}
+
+// replaces the localized name of the month with the english one, so that the date can be parsed
+func (locale Locale) translateMonth(value string) string {
+	for _, months := range [][12]string{locale.Months, locale.MonthsShort} {
+		for position, month := range months {
+			if month != "" && strings.Contains(value, month) {
+				return strings.Replace(value, month, time.Month(position+1).String(), 1)
+			}
+		}
+	}
+
+	return value
+}
+
+// parses a number like "1.000.000" or "1 000 000" by ignoring all separators
+func (locale Locale) parseInteger(value string) (int64, error) {
+	digits := strings.Map(func(character rune) rune {
+		if unicode.IsDigit(character) {
+			return character
+		}
+		return -1
+	}, value)
+	if digits == "" {
+		return 0, errors.New("\"" + value + "\" doesn't contain a number")
+	}
+
+	return strconv.ParseInt(digits, 10, 64)
+}
+
+// parses a number of installs like "1,000,000+", "1B+" or "10 Mio.+" and returns the number it starts with
+func (locale Locale) parseInstalls(value string) (int64, error) {
+	firstDigit := strings.IndexFunc(value, unicode.IsDigit)
+	lastDigit := strings.LastIndexFunc(value, unicode.IsDigit)
+	if firstDigit < 0 {
+		return 0, errors.New("\"" + value + "\" doesn't contain a number")
+	}
+	_, lastDigitSize := utf8.DecodeRuneInString(value[lastDigit:])
+	abbreviation := strings.ToLower(strings.TrimFunc(value[lastDigit+lastDigitSize:], func(character rune) bool {
+		return unicode.IsSpace(character) || character == '.' || character == '+'
+	}))
+	if abbreviation == "" {
+		return locale.parseInteger(value)
+	}
+
+	factor, known := numberAbbreviations[abbreviation]
+	if !known {
+		return 0, errors.New("\"" + value + "\" contains the unknown abbreviation \"" + abbreviation + "\"")
+	}
+	number, parseError := locale.parseDecimal(value[firstDigit : lastDigit+lastDigitSize])
+	if parseError != nil {
+		return 0, parseError
+	}
+
+	return int64(math.Round(number * factor)), nil
+}
+
+// parses a price like "€3.99", "3,99 €" or "US$1,234.50" and returns the value and the currency
+func (locale Locale) parsePrice(value string) (float64, string, error) {
+	var number, currency strings.Builder
+	for _, character := range value {
+		if unicode.IsDigit(character) || (number.Len() > 0 && strings.ContainsRune(".,'", character)) {
+			number.WriteRune(character)
+		} else if !unicode.IsSpace(character) {
+			currency.WriteRune(character)
+		}
+	}
+	price, parseError := locale.parseDecimal(number.String())
+
+	return price, currency.String(), parseError
+}
+
+// parses a decimal number like "4,7" or "1.234,50" written in the language of the locale
+func (locale Locale) parseDecimal(value string) (float64, error) {
+	numberString := strings.TrimRight(strings.TrimSpace(value), ".,'")
+	if numberString == "" {
+		return 0, errors.New("\"" + value + "\" doesn't contain a number")
+	}
+
+	// the separator used last is the decimal separator if both are present, a single one is the decimal separator
+	// if it isn't followed by exactly 3 digits, otherwise the one of the language is used
+	decimalSeparator := locale.DecimalSeparator
+	lastComma := strings.LastIndex(numberString, ",")
+	lastDot := strings.LastIndex(numberString, ".")
+	if lastComma >= 0 && lastDot >= 0 {
+		decimalSeparator = "."
+		if lastComma > lastDot {
+			decimalSeparator = ","
+		}
+	} else if lastComma >= 0 && len(numberString)-lastComma-1 != 3 {
+		decimalSeparator = ","
+	} else if lastDot >= 0 && len(numberString)-lastDot-1 != 3 {
+		decimalSeparator = "."
+	}
+	for _, separator := range []string{".", ",", "'"} {
+		if separator != decimalSeparator {
+			numberString = strings.Replace(numberString, separator, "", -1)
+		}
+	}
+	numberString = strings.Replace(numberString, decimalSeparator, ".", 1)
+
+	return strconv.ParseFloat(numberString, 64)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocaleParseDate(t *testing.T) {
+	for _, testCase := range []struct {
+		language string
+		value    string
+		date     time.Time
+	}{
+		{"en", "October 27, 2017", time.Date(2017, 10, 27, 0, 0, 0, 0, time.UTC)},
+		{"en_GB", "27 October 2017", time.Date(2017, 10, 27, 0, 0, 0, 0, time.UTC)},
+		{"de", "27. Oktober 2017", time.Date(2017, 10, 27, 0, 0, 0, 0, time.UTC)},
+		{"de-AT", "22. März 2018", time.Date(2018, 3, 22, 0, 0, 0, 0, time.UTC)},
+		{"fr", "6 novembre 2018", time.Date(2018, 11, 6, 0, 0, 0, 0, time.UTC)},
+		{"es", "3 de enero de 2019", time.Date(2019, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{"pt_BR", "27 de out. de 2017", time.Date(2017, 10, 27, 0, 0, 0, 0, time.UTC)},
+		{"ja", "2017年10月27日", time.Date(2017, 10, 27, 0, 0, 0, 0, time.UTC)},
+		{"xx", "October 27, 2017", time.Date(2017, 10, 27, 0, 0, 0, 0, time.UTC)},
+	} {
+		date, err := getLocale(testCase.language).parseDate(testCase.value)
+		if err != nil {
+			t.Errorf("%s : could not parse %q : %v", testCase.language, testCase.value, err)
+		} else if !date.Equal(testCase.date) {
+			t.Errorf("%s : %q should be %v, got %v", testCase.language, testCase.value, testCase.date, date)
+		}
+	}
+
+	if _, err := getLocale("en").parseDate("yesterday"); err == nil {
+		t.Errorf("\"yesterday\" should not be parsed as a date")
+	}
+}
+
+func TestLocaleParseInteger(t *testing.T) {
+	for _, testCase := range []struct {
+		language string
+		value    string
+		number   int64
+	}{
+		{"en", "1,000,000,000+", 1000000000},
+		{"de", "1.000.000+", 1000000},
+		{"fr", "10 000+", 10000},
+		{"fr", "5 000+", 5000},
+	} {
+		number, err := getLocale(testCase.language).parseInteger(testCase.value)
+		if err != nil || number != testCase.number {
+			t.Errorf("%s : %q should be %d, got %d (%v)", testCase.language, testCase.value, testCase.number, number, err)
+		}
+	}
+}
+
+func TestLocaleParseInstalls(t *testing.T) {
+	for _, testCase := range []struct {
+		language string
+		value    string
+		installs int64
+	}{
+		{"en", "1,000,000,000+", 1000000000},
+		{"en", "1B+", 1000000000},
+		{"en", "500K+", 500000},
+		{"en", "1.5M+", 1500000},
+		{"de", "1.000.000+", 1000000},
+		{"de", "10 Mio.+", 10000000},
+		{"de", "1 Mrd.+", 1000000000},
+		{"fr", "10\u00a0000+", 10000},
+		{"fr", "5 Md+", 5000000000},
+		{"es", "100 mil+", 100000},
+		{"ja", "1億+", 100000000},
+	} {
+		installs, err := getLocale(testCase.language).parseInstalls(testCase.value)
+		if err != nil || installs != testCase.installs {
+			t.Errorf("%s : %q should be %d, got %d (%v)", testCase.language, testCase.value, testCase.installs, installs, err)
+		}
+	}
+
+	for _, value := range []string{"", "Varies with device", "10 Zillionen+"} {
+		if _, err := getLocale("de").parseInstalls(value); err == nil {
+			t.Errorf("%q should not be parsed", value)
+		}
+	}
+}
+
+func TestLocaleParsePrice(t *testing.T) {
+	for _, testCase := range []struct {
+		language string
+		value    string
+		price    float64
+		currency string
+	}{
+		{"en", "€3.99", 3.99, "€"},
+		{"en", "US$1,234.50", 1234.5, "US$"},
+		{"de", "3,99 €", 3.99, "€"},
+		{"de", "1.234,50 €", 1234.5, "€"},
+		{"pt_BR", "R$ 5,99", 5.99, "R$"},
+		{"de", "CHF 4.00", 4, "CHF"},
+		{"ja", "¥120", 120, "¥"},
+	} {
+		price, currency, err := getLocale(testCase.language).parsePrice(testCase.value)
+		if err != nil || price != testCase.price || currency != testCase.currency {
+			t.Errorf("%s : %q should be %v %s, got %v %s (%v)", testCase.language, testCase.value, testCase.price, testCase.currency, price, currency, err)
+		}
+	}
+}
+
+func TestLocaleMatchesLabel(t *testing.T) {
+	for _, testCase := range []struct {
+		language string
+		text     string
+		value    string
+		matches  bool
+	}{
+		{"en", "Contains Ads", "Contains Ads", true},
+		{"de", "Enthält Werbung", "Contains Ads", true},
+		{"de", "Bietet In-App-Käufe", "Offers in-app purchases", true},
+		{"fr", "Contient des annonces", "Contains Ads", true},
+		{"es", "Varía según el dispositivo", "Varies with device", true},
+		{"de", "Enthält Werbung", "Offers in-app purchases", false},
+		{"de", "4.1 und höher", "Varies with device", false},
+		{"en", "Ads inside", "Ads inside", true},
+	} {
+		if matches := getLocale(testCase.language).matchesLabel(testCase.text, testCase.value); matches != testCase.matches {
+			t.Errorf("%s : %q matching %q should be %v", testCase.language, testCase.text, testCase.value, testCase.matches)
+		}
+	}
+}
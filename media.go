@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/OlegSchmidt/soup"
+)
+
+const (
+	button = "button"
+
+	// attributes of the images, images further down the page are loaded lazily from the data attributes
+	src            = "src"
+	srcset         = "srcset"
+	dataSrc        = "data-src"
+	dataSrcset     = "data-srcset"
+	dataTrailerURL = "data-trailer-url"
+
+	// types of the downloaded media files
+	MediaTypeIcon           = "icon"
+	MediaTypeScreenshot     = "screenshot"
+	MediaTypeFeatureGraphic = "feature_graphic"
+
+	// hosts and paths of the links to the promo video on YouTube
+	youtubeHostShort = "youtu.be"
+	youtubePathEmbed = "/embed/"
+	youtubePathWatch = "/watch"
+)
+
+// file extensions of the image types the Google Play Store serves
+var mediaExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
+// returns the url of the icon of the app
+func getIconURL(document soup.Root, selectors SelectorProfile) (string, error) {
+	informationBlock := document.Find(div, class, selectors.ClassMainInformationAppContainer)
+	if informationBlock.Error != nil {
+		return "", errors.New("iconUrl : main information block \"app\" couldn't be found, looking for <div class=\"" + selectors.ClassMainInformationAppContainer + "\"></div>")
+	}
+	icon := informationBlock.Find(img, class, selectors.ClassAppIcon)
+	if icon.Error != nil {
+		return "", errors.New("iconUrl : there is no <img class=\"" + selectors.ClassAppIcon + "\"> in main information block \"app\"")
+	}
+	iconURL := getImageSource(icon)
+	if iconURL == "" {
+		return "", errors.New("iconUrl : <img class=\"" + selectors.ClassAppIcon + "\"> doesn't have a source")
+	}
+
+	return iconURL, nil
+}
+
+// returns the screenshots in the order they are shown, together with the resolution their urls request
+func getScreenshots(document soup.Root, selectors SelectorProfile) ([]Screenshot, error) {
+	screenshots := []Screenshot{}
+	container := document.Find(div, class, selectors.ClassAppScreenshots)
+	if container.Error != nil {
+		return screenshots, errors.New("screenshots : there is no <div class=\"" + selectors.ClassAppScreenshots + "\"></div>")
+	}
+	for _, image := range container.FindAll(img) {
+		screenshotURL := getImageSource(image)
+		if screenshotURL == "" {
+			continue
+		}
+		screenshot := Screenshot{URL: screenshotURL, HighResolutionURL: getImageHighResolutionSource(image)}
+		screenshot.Width, screenshot.Height = getImageSize(screenshotURL)
+		screenshots = append(screenshots, screenshot)
+	}
+	if len(screenshots) == 0 {
+		return screenshots, errors.New("screenshots : <div class=\"" + selectors.ClassAppScreenshots + "\"></div> doesn't contain any image")
+	}
+
+	return screenshots, nil
+}
+
+// returns the url of the banner shown above the screenshots, not all apps have one
+func getFeatureGraphicURL(document soup.Root, selectors SelectorProfile) (string, error) {
+	container := document.Find(div, class, selectors.ClassAppFeatureGraphic)
+	if container.Error != nil {
+		return "", nil
+	}
+	image := container.Find(img)
+	if image.Error != nil {
+		return "", nil
+	}
+
+	return getImageSource(image), nil
+}
+
+// returns the id of the promo video on YouTube, not all apps have one
+func getPromoVideoID(document soup.Root, selectors SelectorProfile) (string, error) {
+	trailerButton := document.Find(button, class, selectors.ClassAppPromoVideo)
+	if trailerButton.Error != nil {
+		return "", nil
+	}
+	trailerURL := trailerButton.GetAttribute(dataTrailerURL)
+	videoID := getYouTubeVideoID(trailerURL)
+	if videoID == "" {
+		return "", errors.New("promoVideoId : \"" + trailerURL + "\" of <button class=\"" + selectors.ClassAppPromoVideo + "\"></button> is no link to a YouTube video")
+	}
+
+	return videoID, nil
+}
+
+// returns the source of the image, lazily loaded images only have it in the data attribute
+func getImageSource(image soup.Root) string {
+	if source := image.GetAttribute(src); source != "" {
+		return source
+	}
+
+	return image.GetAttribute(dataSrc)
+}
+
+// returns the last, so largest, source of the srcset of the image, empty if it doesn't have one
+func getImageHighResolutionSource(image soup.Root) string {
+	sourceSet := image.GetAttribute(srcset)
+	if sourceSet == "" {
+		sourceSet = image.GetAttribute(dataSrcset)
+	}
+	sources := strings.Split(sourceSet, ",")
+	source := strings.Fields(sources[len(sources)-1])
+	if len(source) == 0 {
+		return ""
+	}
+
+	return source[0]
+}
+
+// returns the width and the height an image url of googleusercontent.com requests, the options after the last "=" look
+// like "w720-h310" or "s180" for the longer side, sizes which are not given are zero
+func getImageSize(imageURL string) (int, int) {
+	var width, height int
+	name := imageURL[strings.LastIndex(imageURL, "/")+1:]
+	optionsPosition := strings.LastIndex(name, "=")
+	if optionsPosition < 0 {
+		return width, height
+	}
+	for _, option := range strings.Split(name[optionsPosition+1:], "-") {
+		if len(option) < 2 {
+			continue
+		}
+		size, parseError := strconv.Atoi(option[1:])
+		if parseError != nil {
+			continue
+		}
+		switch option[0] {
+		case 's':
+			width, height = size, size
+		case 'w':
+			width = size
+		case 'h':
+			height = size
+		}
+	}
+
+	return width, height
+}
+
+// returns the id of the video of an embedded, a shortened or a regular link to YouTube, empty for other links
+func getYouTubeVideoID(link string) string {
+	linkURL, parseError := url.Parse(link)
+	if parseError != nil {
+		return ""
+	}
+	switch {
+	case linkURL.Host == youtubeHostShort:
+		return strings.Trim(linkURL.Path, "/")
+	case strings.HasPrefix(linkURL.Path, youtubePathEmbed):
+		return strings.Trim(strings.TrimPrefix(linkURL.Path, youtubePathEmbed), "/")
+	case linkURL.Path == youtubePathWatch:
+		return linkURL.Query().Get("v")
+	}
+
+	return ""
+}
+
+// returns the images of the app page which are downloaded, the sizes and hashes are not known yet
+func getMediaFiles(appPage AppPage) []MediaFile {
+	var mediaFiles []MediaFile
+	if appPage.IconURL != "" {
+		mediaFiles = append(mediaFiles, MediaFile{Type: MediaTypeIcon, URL: appPage.IconURL})
+	}
+	for _, screenshot := range appPage.Screenshots {
+		mediaFiles = append(mediaFiles, MediaFile{Type: MediaTypeScreenshot, URL: screenshot.URL})
+	}
+	if appPage.FeatureGraphicURL != "" {
+		mediaFiles = append(mediaFiles, MediaFile{Type: MediaTypeFeatureGraphic, URL: appPage.FeatureGraphicURL})
+	}
+
+	return mediaFiles
+}
+
+// downloads the images of the app page into a directory of the app inside the media directory, every file is named
+// after the sha-256 hash of its content, so an image only gets a new file once it looks different. Images which could
+// not be downloaded are left out and reported in the returned errors
+func downloadMedia(ctx context.Context, appPage AppPage, options CrawlOptions) ([]MediaFile, []string) {
+	mediaFiles := []MediaFile{}
+	var downloadErrors []string
+
+	directory := filepath.Join(options.MediaDirectory, appPage.PackageName)
+	directoryError := os.MkdirAll(directory, 0755)
+	if directoryError != nil {
+		return mediaFiles, []string{"media_files : could not create the directory \"" + directory + "\" : " + directoryError.Error()}
+	}
+	for _, mediaFile := range getMediaFiles(appPage) {
+		if ctx.Err() != nil {
+			break
+		}
+		downloadError := downloadMediaFile(ctx, options.Fetcher, directory, &mediaFile)
+		if downloadError != nil {
+			downloadErrors = append(downloadErrors, "media_files : could not download \""+mediaFile.URL+"\" : "+downloadError.Error())
+			continue
+		}
+		mediaFiles = append(mediaFiles, mediaFile)
+	}
+
+	return mediaFiles, downloadErrors
+}
+
+// downloads the image of the media file into the directory and fills in its path and hash, files with the same content
+// are only written once
+func downloadMediaFile(ctx context.Context, fetcher Fetcher, directory string, mediaFile *MediaFile) error {
+	response, fetchError := fetcher.Fetch(ctx, mediaFile.URL)
+	if fetchError != nil {
+		return fetchError
+	}
+	if response.StatusCode != http.StatusOK {
+		return errors.New("status code " + strconv.Itoa(response.StatusCode))
+	}
+
+	hash := sha256.Sum256([]byte(response.Body))
+	mediaFile.SHA256 = hex.EncodeToString(hash[:])
+	mediaType := strings.TrimSpace(strings.Split(response.Header.Get("Content-Type"), ";")[0])
+	mediaFile.Path = filepath.Join(directory, mediaFile.SHA256+mediaExtensions[mediaType])
+	if _, statError := os.Stat(mediaFile.Path); statError == nil {
+		return nil
+	}
+
+	return ioutil.WriteFile(mediaFile.Path, []byte(response.Body), 0644)
+}
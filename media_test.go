@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// mediaFetcher serves the app pages from the test server and the images from memory
+type mediaFetcher struct {
+	fetcher Fetcher
+	images  map[string]string
+}
+
+func (fetcher mediaFetcher) Fetch(ctx context.Context, url string) (FetchResponse, error) {
+	if strings.HasPrefix(url, baseURL) {
+		return fetcher.fetcher.Fetch(ctx, url)
+	}
+	image, found := fetcher.images[url]
+	if !found {
+		return FetchResponse{StatusCode: http.StatusNotFound, Header: http.Header{}, URL: url}, nil
+	}
+
+	return FetchResponse{Body: image, StatusCode: http.StatusOK, Header: http.Header{"Content-Type": {"image/png"}}, URL: url}, nil
+}
+
+func TestDownloadMedia(t *testing.T) {
+	pages := newTestFetcher(loadAppPageFixtures())
+	defer pages.Close()
+	directory, _ := ioutil.TempDir("", "media")
+	defer os.RemoveAll(directory)
+
+	appPage, err := Crawl("com.whatsapp", CrawlOptions{Fetcher: pages})
+	if err != nil {
+		t.Fatalf("app page should be crawled, got %v", err)
+	}
+	// the icon and the feature graphic look the same, the last screenshot is missing
+	images := map[string]string{
+		appPage.IconURL:            "icon",
+		appPage.Screenshots[0].URL: "first screenshot",
+		appPage.Screenshots[1].URL: "second screenshot",
+		appPage.FeatureGraphicURL:  "icon",
+	}
+
+	appPage, err = Crawl("com.whatsapp", CrawlOptions{Fetcher: mediaFetcher{fetcher: pages, images: images}, MediaDirectory: directory})
+	if err != nil {
+		t.Fatalf("app page should be crawled, got %v", err)
+	}
+	if len(appPage.MediaFiles) != 4 {
+		t.Fatalf("all images except the missing screenshot should be downloaded, got %v", appPage.MediaFiles)
+	}
+	if !containsError(appPage.Errors, "media_files : could not download \""+appPage.Screenshots[2].URL+"\"") {
+		t.Errorf("the missing screenshot should be reported, got %v", appPage.Errors)
+	}
+	for _, mediaFile := range appPage.MediaFiles {
+		hash := sha256.Sum256([]byte(images[mediaFile.URL]))
+		if mediaFile.SHA256 != hex.EncodeToString(hash[:]) {
+			t.Errorf("hash of %s should be the hash of its content, got %s", mediaFile.URL, mediaFile.SHA256)
+		}
+		if mediaFile.Path != filepath.Join(directory, "com.whatsapp", mediaFile.SHA256+".png") {
+			t.Errorf("file of %s should be named after its hash, got %s", mediaFile.URL, mediaFile.Path)
+		}
+		if content, readError := ioutil.ReadFile(mediaFile.Path); readError != nil || string(content) != images[mediaFile.URL] {
+			t.Errorf("file of %s should contain the image, got %q (%v)", mediaFile.URL, content, readError)
+		}
+	}
+	if appPage.MediaFiles[0].Type != MediaTypeIcon || appPage.MediaFiles[3].Type != MediaTypeFeatureGraphic || appPage.MediaFiles[0].Path != appPage.MediaFiles[3].Path {
+		t.Errorf("images with the same content should share their file, got %v", appPage.MediaFiles)
+	}
+}
+
+// returns if one of the errors starts with the prefix
+func containsError(errorList []string, prefix string) bool {
+	for _, entry := range errorList {
+		if strings.HasPrefix(entry, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestImageSize(t *testing.T) {
+	for imageURL, want := range map[string][2]int{
+		"https://lh3.googleusercontent.com/abc=w720-h310":   {720, 310},
+		"https://lh3.googleusercontent.com/abc=s180":        {180, 180},
+		"https://lh3.googleusercontent.com/abc=h310-rw":     {0, 310},
+		"https://play-lh.googleusercontent.com/abc-def_ghi": {0, 0},
+	} {
+		if width, height := getImageSize(imageURL); width != want[0] || height != want[1] {
+			t.Errorf("size of %q should be %v, got %dx%d", imageURL, want, width, height)
+		}
+	}
+}
+
+func TestYouTubeVideoID(t *testing.T) {
+	for link, want := range map[string]string{
+		"https://www.youtube.com/embed/Q3tyfQ3hW5c?ps=play&vq=large": "Q3tyfQ3hW5c",
+		"https://www.youtube.com/watch?v=Q3tyfQ3hW5c":                "Q3tyfQ3hW5c",
+		"https://youtu.be/Q3tyfQ3hW5c":                               "Q3tyfQ3hW5c",
+		"https://vimeo.com/123456":                                   "",
+	} {
+		if got := getYouTubeVideoID(link); got != want {
+			t.Errorf("video id of %q should be %q, got %q", link, want, got)
+		}
+	}
+}
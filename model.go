@@ -3,38 +3,103 @@ package main
 import (
 	"strconv"
 	"strings"
+	"time"
+)
+
+const (
+	// versions of the response format of app pages, version 1 returns the dates as numbers like 20181231 and stays the
+	// default of the routes without the "/v2" prefix until all clients moved to the timestamps of version 2
+	FormatVersionLegacy  = 1
+	FormatVersionCurrent = 2
 )
 
 // AppPage model
 type AppPage struct {
-	Name                    string             `json:"name" bson:"name"`
-	PackageName             string             `json:"package_name" bson:"package_name"`
-	DateCrawled             int64              `json:"date_crawled" bson:"date_crawled"`
-	Category                string             `json:"category" bson:"category"`
-	USK                     string             `json:"usk" bson:"usk"`
-	Price                   string             `json:"price" bson:"price"`
-	PriceValue              float64            `json:"price_value" bson:"price_value"`
-	PriceCurrency           string             `json:"price_currency" bson:"price_currency"`
-	Description             string             `json:"description" bson:"description"`
-	WhatsNew                []string           `json:"whats_new" bson:"whats_new"`
-	Rating                  float64            `json:"rating" bson:"rating"`
-	StarsCount              int64              `json:"stars_count" bson:"stars_count"`
-	CountPerRating          StarCountPerRating `json:"count_per_rating" bson:"count_per_rating"`
-	EstimatedDownloadNumber int64              `json:"estimated_download_number" bson:"estimated_download_number"`
-	DeveloperName           string             `json:"developer" bson:"developer"`
-	TopDeveloper            bool               `json:"top_developer" bson:"top_developer"`
-	ContainsAds             bool               `json:"contains_ads" bson:"contains_ads"`
-	InAppPurchases          bool               `json:"in_app_purchase" bson:"in_app_purchase"`
-	LastUpdate              int64              `json:"last_update" bson:"last_update"`
-	Os                      string             `json:"os" bson:"os"`
-	RequiresOsVersion       string             `json:"requires_os_version" bson:"requires_os_version"`
-	CurrentSoftwareVersion  string             `json:"current_software_version" bson:"current_software_version"`
-	SimilarApps             []string           `json:"similar_apps" bson:"similar_apps"`
-	Errors                  []string           `json:"errors" bson:"errors"`
+	Name                     string               `json:"name" bson:"name"`
+	PackageName              string               `json:"package_name" bson:"package_name"`
+	DateCrawled              time.Time            `json:"date_crawled" bson:"date_crawled"`
+	Language                 string               `json:"language" bson:"language"`
+	Country                  string               `json:"country" bson:"country"`
+	SelectorProfileVersion   string               `json:"selector_profile_version" bson:"selector_profile_version"`
+	Category                 string               `json:"category" bson:"category"`
+	USK                      string               `json:"usk" bson:"usk"`
+	Price                    string               `json:"price" bson:"price"`
+	PriceValue               float64              `json:"price_value" bson:"price_value"`
+	PriceCurrency            string               `json:"price_currency" bson:"price_currency"`
+	Description              string               `json:"description" bson:"description"`
+	WhatsNew                 []string             `json:"whats_new" bson:"whats_new"`
+	Rating                   float64              `json:"rating" bson:"rating"`
+	StarsCount               int64                `json:"stars_count" bson:"stars_count"`
+	CountPerRating           StarCountPerRating   `json:"count_per_rating" bson:"count_per_rating"`
+	PercentPerRating         StarPercentPerRating `json:"percent_per_rating" bson:"percent_per_rating"`
+	EstimatedDownloadNumber  int64                `json:"estimated_download_number" bson:"estimated_download_number"`
+	RealInstalls             int64                `json:"real_installs" bson:"real_installs"`
+	InstallBucket            string               `json:"install_bucket" bson:"install_bucket"`
+	DeveloperName            string               `json:"developer" bson:"developer"`
+	DeveloperID              string               `json:"developer_id" bson:"developer_id"`
+	TopDeveloper             bool                 `json:"top_developer" bson:"top_developer"`
+	ContainsAds              bool                 `json:"contains_ads" bson:"contains_ads"`
+	InAppPurchases           bool                 `json:"in_app_purchase" bson:"in_app_purchase"`
+	LastUpdate               *time.Time           `json:"last_update" bson:"last_update"`
+	ReleaseDate              *time.Time           `json:"release_date" bson:"release_date"`
+	Os                       string               `json:"os" bson:"os"`
+	RequiresOsVersion        string               `json:"requires_os_version" bson:"requires_os_version"`
+	CurrentSoftwareVersion   string               `json:"current_software_version" bson:"current_software_version"`
+	Size                     string               `json:"size" bson:"size"`
+	ContentRatingDescriptors []string             `json:"content_rating_descriptors" bson:"content_rating_descriptors"`
+	InteractiveElements      []string             `json:"interactive_elements" bson:"interactive_elements"`
+	InAppProducts            string               `json:"in_app_products" bson:"in_app_products"`
+	OfferedBy                string               `json:"offered_by" bson:"offered_by"`
+	DeveloperWebsite         string               `json:"developer_website" bson:"developer_website"`
+	DeveloperEmail           string               `json:"developer_email" bson:"developer_email"`
+	DeveloperAddress         string               `json:"developer_address" bson:"developer_address"`
+	PrivacyPolicyURL         string               `json:"privacy_policy_url" bson:"privacy_policy_url"`
+	DataSafety               DataSafety           `json:"data_safety" bson:"data_safety"`
+	Permissions              []PermissionGroup    `json:"permissions" bson:"permissions"`
+	IconURL                  string               `json:"icon_url" bson:"icon_url"`
+	Screenshots              []Screenshot         `json:"screenshots" bson:"screenshots"`
+	FeatureGraphicURL        string               `json:"feature_graphic_url" bson:"feature_graphic_url"`
+	PromoVideoID             string               `json:"promo_video_id" bson:"promo_video_id"`
+	MediaFiles               []MediaFile          `json:"media_files" bson:"media_files"`
+	SimilarApps              []string             `json:"similar_apps" bson:"similar_apps"`
+	Blocked                  bool                 `json:"blocked" bson:"blocked"`
+	FieldSources             map[string]string    `json:"field_sources" bson:"field_sources"`
+	Errors                   []string             `json:"errors" bson:"errors"`
+}
+
+// LegacyAppPage model, an app page in response format version 1, the dates are days in UTC formatted like 20181231
+// and zero if they are not known
+type LegacyAppPage struct {
+	AppPage
+	DateCrawled int64 `json:"date_crawled"`
+	LastUpdate  int64 `json:"last_update"`
+	ReleaseDate int64 `json:"release_date"`
+}
+
+// returns the app page in response format version 1
+func (appPage AppPage) legacy() LegacyAppPage {
+	legacyAppPage := LegacyAppPage{AppPage: appPage, DateCrawled: getDayNumber(appPage.DateCrawled)}
+	if appPage.LastUpdate != nil {
+		legacyAppPage.LastUpdate = getDayNumber(*appPage.LastUpdate)
+	}
+	if appPage.ReleaseDate != nil {
+		legacyAppPage.ReleaseDate = getDayNumber(*appPage.ReleaseDate)
+	}
+
+	return legacyAppPage
 }
 
-// StarCountPerRating model
+// StarCountPerRating model, the number of ratings with each amount of stars
 type StarCountPerRating struct {
+	Five  int64 `json:"5"`
+	Four  int64 `json:"4"`
+	Three int64 `json:"3"`
+	Two   int64 `json:"2"`
+	One   int64 `json:"1"`
+}
+
+// StarPercentPerRating model, the share of the ratings with each amount of stars in percent
+type StarPercentPerRating struct {
 	Five  int `json:"5"`
 	Four  int `json:"4"`
 	Three int `json:"3"`
@@ -42,6 +107,53 @@ type StarCountPerRating struct {
 	One   int `json:"1"`
 }
 
+// DataSafety model, what the developer declares about the data the app collects and shares with third parties
+type DataSafety struct {
+	SharedData    []DataSafetyEntry `json:"shared_data" bson:"shared_data"`
+	CollectedData []DataSafetyEntry `json:"collected_data" bson:"collected_data"`
+	// purposes of all shared and collected data
+	Purposes          []string          `json:"purposes" bson:"purposes"`
+	SecurityPractices SecurityPractices `json:"security_practices" bson:"security_practices"`
+}
+
+// DataSafetyEntry model, a type of data like "Approximate location" of a category like "Location"
+type DataSafetyEntry struct {
+	Category string   `json:"category" bson:"category"`
+	Type     string   `json:"type" bson:"type"`
+	Optional bool     `json:"optional" bson:"optional"`
+	Purposes []string `json:"purposes" bson:"purposes"`
+}
+
+// SecurityPractices model
+type SecurityPractices struct {
+	EncryptedInTransit bool `json:"encrypted_in_transit" bson:"encrypted_in_transit"`
+	// users can request that their data is deleted
+	DeletionRequest bool `json:"deletion_request" bson:"deletion_request"`
+}
+
+// PermissionGroup model, the permissions of the app grouped like "Location" or "Storage"
+type PermissionGroup struct {
+	Group       string   `json:"group" bson:"group"`
+	Permissions []string `json:"permissions" bson:"permissions"`
+}
+
+// Screenshot model, the url of a screenshot together with the resolution it is served in, unknown sizes are zero
+type Screenshot struct {
+	URL    string `json:"url" bson:"url"`
+	Width  int    `json:"width" bson:"width"`
+	Height int    `json:"height" bson:"height"`
+	// url of the same screenshot for high density displays, empty if the page doesn't offer one
+	HighResolutionURL string `json:"high_resolution_url" bson:"high_resolution_url"`
+}
+
+// MediaFile model, a downloaded image of the app page, the hash of its content changes with the image
+type MediaFile struct {
+	Type   string `json:"type" bson:"type"`
+	URL    string `json:"url" bson:"url"`
+	Path   string `json:"path" bson:"path"`
+	SHA256 string `json:"sha256" bson:"sha256"`
+}
+
 // style property model
 type AttributeStyle struct {
 	Name  string
@@ -49,10 +161,14 @@ type AttributeStyle struct {
 	Unit  string
 }
 
-// fills an object with given definition
+// fills an object with given definition, an empty style is returned for definitions without ":" like the empty one
+// after a trailing ";"
 func (style AttributeStyle) fill(definition string) AttributeStyle {
 	definition = strings.TrimSpace(definition)
-	definitionParts := strings.Split(definition, ":")
+	definitionParts := strings.SplitN(definition, ":", 2)
+	if len(definitionParts) < 2 {
+		return AttributeStyle{}
+	}
 	value := definitionParts[1]
 	unit := ""
 	units := [4]string{"%", "px", "rem", "em"}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRateLimitPerMinute = 60
+	defaultRateLimitBurst     = 5
+)
+
+// HostRateLimiter limits the requests per host with a token bucket for each host, it is shared by all goroutines
+// fetching pages so that the crawler as a whole stays below the limit
+type HostRateLimiter struct {
+	// tokens added per second and maximum number of tokens of a bucket
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mutex   sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// tokens left for a host, they become negative if requests are waiting for tokens
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewHostRateLimiter returns a limiter allowing perMinute requests per host, with at most burst requests at once
+func NewHostRateLimiter(perMinute int, burst int) *HostRateLimiter {
+	return &HostRateLimiter{
+		rate:    float64(perMinute) / 60,
+		burst:   float64(burst),
+		now:     time.Now,
+		buckets: map[string]*tokenBucket{},
+	}
+}
+
+// Wait blocks until the host of the url may be requested or the context is done
+func (limiter *HostRateLimiter) Wait(ctx context.Context, rawURL string) error {
+	host := rawURL
+	if parsedURL, parseError := url.Parse(rawURL); parseError == nil {
+		host = parsedURL.Host
+	}
+
+	return sleepContext(ctx, limiter.reserve(host))
+}
+
+// takes a token of the host and returns how long to wait until it is available
+func (limiter *HostRateLimiter) reserve(host string) time.Duration {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	now := limiter.now()
+	bucket, exists := limiter.buckets[host]
+	if !exists {
+		bucket = &tokenBucket{tokens: limiter.burst, updated: now}
+		limiter.buckets[host] = bucket
+	}
+	bucket.tokens += now.Sub(bucket.updated).Seconds() * limiter.rate
+	if bucket.tokens > limiter.burst {
+		bucket.tokens = limiter.burst
+	}
+	bucket.updated = now
+
+	bucket.tokens--
+	if bucket.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-bucket.tokens / limiter.rate * float64(time.Second))
+}
+
+// waits for the duration, it returns early with the error of the context if the context is done before
+func sleepContext(ctx context.Context, duration time.Duration) error {
+	if duration <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
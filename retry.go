@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultFetchRetries          = 2
+	defaultFetchBackoffMillis    = 500
+	defaultFetchMaxBackoffMillis = 10000
+
+	// errors
+	errorFetcherCannotPost = "the fetcher can't send forms"
+)
+
+// RetryFetcher retries transient failures of another fetcher with exponential backoff and jitter, every attempt waits
+// for the rate limiter of the host first
+type RetryFetcher struct {
+	Fetcher Fetcher
+	// number of attempts after the first one
+	Retries   int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// nil doesn't limit the requests
+	Limiter *HostRateLimiter
+
+	sleep       func(ctx context.Context, duration time.Duration) error
+	randomMutex sync.Mutex
+	random      *rand.Rand
+}
+
+// NewRetryFetcher returns a fetcher retrying the requests of the given fetcher
+func NewRetryFetcher(fetcher Fetcher, retries int, baseDelay time.Duration, maxDelay time.Duration, limiter *HostRateLimiter) *RetryFetcher {
+	return &RetryFetcher{
+		Fetcher:   fetcher,
+		Retries:   retries,
+		BaseDelay: baseDelay,
+		MaxDelay:  maxDelay,
+		Limiter:   limiter,
+		sleep:     sleepContext,
+		random:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Fetch requests the url until it succeeds, the error isn't transient or all retries are used up, the response of
+// the last attempt is returned
+func (fetcher *RetryFetcher) Fetch(ctx context.Context, url string) (FetchResponse, error) {
+	return fetcher.retry(ctx, url, func() (FetchResponse, error) {
+		return fetcher.Fetcher.Fetch(ctx, url)
+	})
+}
+
+// Post sends the form like Fetch requests the url, it fails if the wrapped fetcher can't send forms
+func (fetcher *RetryFetcher) Post(ctx context.Context, url string, form url.Values) (FetchResponse, error) {
+	poster, isPoster := fetcher.Fetcher.(Poster)
+	if !isPoster {
+		return FetchResponse{}, errors.New(errorFetcherCannotPost)
+	}
+
+	return fetcher.retry(ctx, url, func() (FetchResponse, error) {
+		return poster.Post(ctx, url, form)
+	})
+}
+
+// repeats the attempt until it succeeds, the error isn't transient or all retries are used up
+func (fetcher *RetryFetcher) retry(ctx context.Context, url string, attempt func() (FetchResponse, error)) (FetchResponse, error) {
+	var response FetchResponse
+	var fetchError error
+
+	for attemptNumber := 0; ; attemptNumber++ {
+		if fetcher.Limiter != nil {
+			waitError := fetcher.Limiter.Wait(ctx, url)
+			if waitError != nil {
+				return response, waitError
+			}
+		}
+
+		response, fetchError = attempt()
+		delay, retry := fetcher.retryDelay(attemptNumber, response, fetchError)
+		if !retry || attemptNumber >= fetcher.Retries {
+			return response, fetchError
+		}
+		sleepError := fetcher.sleep(ctx, delay)
+		if sleepError != nil {
+			return response, fetchError
+		}
+	}
+}
+
+// returns whether the attempt failed because of a transient error and how long to wait before the next one
+func (fetcher *RetryFetcher) retryDelay(attempt int, response FetchResponse, fetchError error) (time.Duration, bool) {
+	if fetchError != nil {
+		var netError net.Error
+		if errors.As(fetchError, &netError) && netError.Timeout() {
+			return fetcher.backoff(attempt), true
+		}
+		// the connection was dropped by the server or a proxy in between, e.g. while it was restarted
+		if errors.Is(fetchError, syscall.ECONNRESET) || errors.Is(fetchError, io.ErrUnexpectedEOF) {
+			return fetcher.backoff(attempt), true
+		}
+		return 0, false
+	}
+
+	switch {
+	case response.StatusCode == http.StatusTooManyRequests:
+		// without Retry-After the request was blocked, retrying right away would only make it worse
+		retryAfter, known := parseRetryAfter(response.Header.Get("Retry-After"))
+		if !known || retryAfter > fetcher.MaxDelay {
+			return 0, false
+		}
+		return retryAfter, true
+	case response.StatusCode >= http.StatusInternalServerError:
+		return fetcher.backoff(attempt), true
+	}
+
+	return 0, false
+}
+
+// returns the delay before the next attempt, it doubles with every attempt and is randomly shortened by up to a half
+func (fetcher *RetryFetcher) backoff(attempt int) time.Duration {
+	delay := fetcher.MaxDelay
+	if attempt < 30 && fetcher.BaseDelay<<uint(attempt) < fetcher.MaxDelay {
+		delay = fetcher.BaseDelay << uint(attempt)
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	fetcher.randomMutex.Lock()
+	defer fetcher.randomMutex.Unlock()
+
+	return delay/2 + time.Duration(fetcher.random.Int63n(int64(delay/2)+1))
+}
+
+// returns the duration of the Retry-After header, given either in seconds or as date, and whether it is known
+func parseRetryAfter(retryAfter string) (time.Duration, bool) {
+	if retryAfter == "" {
+		return 0, false
+	}
+	if seconds, parseError := strconv.Atoi(retryAfter); parseError == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, parseError := http.ParseTime(retryAfter); parseError == nil {
+		duration := time.Until(date)
+		if duration < 0 {
+			duration = 0
+		}
+		return duration, true
+	}
+
+	return 0, false
+}
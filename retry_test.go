@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// returns a server answering with the given status codes one after another, and 200 afterwards
+func newStatusServer(statusCodes []int, header http.Header) (*httptest.Server, *int) {
+	var mutex sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		for name, values := range header {
+			w.Header()[name] = values
+		}
+		if requests < len(statusCodes) {
+			w.WriteHeader(statusCodes[requests])
+		}
+		requests++
+	}))
+
+	return server, &requests
+}
+
+func TestRetryFetcher(t *testing.T) {
+	var delays []time.Duration
+	newFetcher := func(retries int) *RetryFetcher {
+		fetcher := NewRetryFetcher(NewHTTPFetcher(), retries, 100*time.Millisecond, time.Second, nil)
+		fetcher.sleep = func(ctx context.Context, duration time.Duration) error {
+			delays = append(delays, duration)
+			return nil
+		}
+		return fetcher
+	}
+
+	// server errors are retried with growing delays
+	server, requests := newStatusServer([]int{http.StatusServiceUnavailable, http.StatusBadGateway}, nil)
+	response, err := newFetcher(3).Fetch(context.Background(), server.URL)
+	server.Close()
+	if err != nil || response.StatusCode != http.StatusOK || *requests != 3 {
+		t.Errorf("request should succeed with the third attempt, got status %d after %d requests", response.StatusCode, *requests)
+	}
+	if len(delays) != 2 || delays[0] < 50*time.Millisecond || delays[0] > 100*time.Millisecond || delays[1] < 100*time.Millisecond || delays[1] > 200*time.Millisecond {
+		t.Errorf("delays should double with every attempt, got %v", delays)
+	}
+
+	// the last response is returned if all retries are used up
+	server, requests = newStatusServer([]int{500, 500, 500}, nil)
+	response, _ = newFetcher(1).Fetch(context.Background(), server.URL)
+	server.Close()
+	if response.StatusCode != http.StatusInternalServerError || *requests != 2 {
+		t.Errorf("request should fail after 2 attempts, got status %d after %d requests", response.StatusCode, *requests)
+	}
+
+	// too many requests is only retried if the server tells when
+	delays = nil
+	server, requests = newStatusServer([]int{http.StatusTooManyRequests}, http.Header{"Retry-After": []string{"1"}})
+	response, _ = newFetcher(2).Fetch(context.Background(), server.URL)
+	server.Close()
+	if response.StatusCode != http.StatusOK || len(delays) != 1 || delays[0] != time.Second {
+		t.Errorf("request should be retried after 1s, got status %d and delays %v", response.StatusCode, delays)
+	}
+	server, requests = newStatusServer([]int{http.StatusTooManyRequests}, nil)
+	response, _ = newFetcher(2).Fetch(context.Background(), server.URL)
+	server.Close()
+	if response.StatusCode != http.StatusTooManyRequests || *requests != 1 {
+		t.Errorf("blocked request should not be retried, got status %d after %d requests", response.StatusCode, *requests)
+	}
+
+	// not found is not transient
+	server, requests = newStatusServer([]int{http.StatusNotFound}, nil)
+	newFetcher(2).Fetch(context.Background(), server.URL)
+	server.Close()
+	if *requests != 1 {
+		t.Errorf("not found should not be retried, got %d requests", *requests)
+	}
+
+	// a body cut off by the server is retried
+	requests = new(int)
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if *requests == 1 {
+			w.Header().Set("Content-Length", "100")
+			w.Write([]byte("cut off"))
+			return
+		}
+		w.Write([]byte("complete"))
+	}))
+	response, err = newFetcher(2).Fetch(context.Background(), server.URL)
+	server.Close()
+	if err != nil || string(response.Body) != "complete" || *requests != 2 {
+		t.Errorf("cut off body should be retried, got %q after %d requests (%v)", response.Body, *requests, err)
+	}
+}
+
+func TestRetryFetcherRetryDelay(t *testing.T) {
+	fetcher := NewRetryFetcher(NewHTTPFetcher(), 2, 100*time.Millisecond, time.Second, nil)
+	for _, testCase := range []struct {
+		err   error
+		retry bool
+	}{
+		{&url.Error{Op: "Get", URL: "https://play.google.com", Err: &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}}, true},
+		{&url.Error{Op: "Get", URL: "https://play.google.com", Err: io.ErrUnexpectedEOF}, true},
+		{&url.Error{Op: "Get", URL: "https://play.google.com", Err: &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}}, false},
+		{errors.New("unsupported protocol scheme"), false},
+	} {
+		if _, retry := fetcher.retryDelay(0, FetchResponse{}, testCase.err); retry != testCase.retry {
+			t.Errorf("retrying %v should be %v", testCase.err, testCase.retry)
+		}
+	}
+}
+
+func TestHostRateLimiter(t *testing.T) {
+	limiter := NewHostRateLimiter(60, 2)
+	now := time.Date(2019, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	// the burst is available right away, then one request per second
+	waits := []time.Duration{limiter.reserve("play.google.com"), limiter.reserve("play.google.com"), limiter.reserve("play.google.com"), limiter.reserve("play.google.com")}
+	want := []time.Duration{0, 0, time.Second, 2 * time.Second}
+	for position := range want {
+		if waits[position] != want[position] {
+			t.Errorf("request %d should wait %v, got %v", position+1, want[position], waits[position])
+		}
+	}
+	if wait := limiter.reserve("example.com"); wait != 0 {
+		t.Errorf("other hosts should have their own bucket, got wait %v", wait)
+	}
+
+	now = now.Add(10 * time.Second)
+	if wait := limiter.reserve("play.google.com"); wait != 0 {
+		t.Errorf("bucket should be refilled, got wait %v", wait)
+	}
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// endpoint of the rpc calls the Google Play Store makes from its pages
+	baseURLBatchExecute = baseURL + "/_/PlayStoreUi/data/batchexecute"
+	// rpc returning a page of reviews
+	reviewRPCID = "UsvDTd"
+	// the response starts with this prefix to prevent json hijacking
+	batchExecutePrefix = ")]}'"
+
+	// sort orders of the reviews
+	ReviewSortNewest       = "newest"
+	ReviewSortMostRelevant = "most_relevant"
+	ReviewSortRating       = "rating"
+
+	defaultReviewLimit = 100
+	maxReviewLimit     = 1000
+	// the rpc doesn't return more reviews at once
+	reviewsPerRequest = 100
+
+	// errors
+	errorReviewResponse = "response of the review rpc could not be read"
+)
+
+// ids of the sort orders used by the rpc
+var reviewSortIDs = map[string]int{
+	ReviewSortMostRelevant: 1,
+	ReviewSortNewest:       2,
+	ReviewSortRating:       3,
+}
+
+// paths to the fields of a review in the rpc response
+var (
+	reviewPathID            = []int{0}
+	reviewPathAuthor        = []int{1, 0}
+	reviewPathRating        = []int{2}
+	reviewPathText          = []int{4}
+	reviewPathDate          = []int{5, 0}
+	reviewPathThumbsUpCount = []int{6}
+	reviewPathReply         = []int{7}
+	reviewPathReplyText     = []int{7, 1}
+	reviewPathReplyDate     = []int{7, 2, 0}
+	reviewPathAppVersion    = []int{10}
+)
+
+// Review model
+type Review struct {
+	ReviewID       string          `json:"review_id" bson:"review_id"`
+	Author         string          `json:"author" bson:"author"`
+	Rating         int             `json:"rating" bson:"rating"`
+	Text           string          `json:"text" bson:"text"`
+	Date           time.Time       `json:"date" bson:"date"`
+	ThumbsUpCount  int64           `json:"thumbs_up_count" bson:"thumbs_up_count"`
+	AppVersion     string          `json:"app_version" bson:"app_version"`
+	DeveloperReply *DeveloperReply `json:"developer_reply" bson:"developer_reply"`
+}
+
+// DeveloperReply model
+type DeveloperReply struct {
+	Text string    `json:"text" bson:"text"`
+	Date time.Time `json:"date" bson:"date"`
+}
+
+// ReviewPage model, the reviews of an app together with the token to request the following ones
+type ReviewPage struct {
+	PackageName       string   `json:"package_name"`
+	Language          string   `json:"language"`
+	Country           string   `json:"country"`
+	Sort              string   `json:"sort"`
+	Reviews           []Review `json:"reviews"`
+	ContinuationToken string   `json:"continuation_token"`
+}
+
+// ReviewOptions model, selects which reviews are crawled
+type ReviewOptions struct {
+	Sort string
+	// only reviews with this number of stars, zero returns all reviews
+	Rating int
+	// maximum number of reviews
+	Limit int
+	// token of a previous review page to continue with its following reviews
+	ContinuationToken string
+}
+
+// fills the options which were not set with their defaults
+func (reviewOptions ReviewOptions) withDefaults() ReviewOptions {
+	if reviewOptions.Sort == "" {
+		reviewOptions.Sort = ReviewSortNewest
+	}
+	if reviewOptions.Limit <= 0 {
+		reviewOptions.Limit = defaultReviewLimit
+	}
+
+	return reviewOptions
+}
+
+// CrawlReviews pages through the reviews of the app until the limit is reached or there are no more reviews, the
+// returned error wraps the same errors as CrawlContext
+func CrawlReviews(ctx context.Context, packageName string, reviewOptions ReviewOptions, options CrawlOptions) (ReviewPage, error) {
+	options = options.withDefaults()
+	reviewOptions = reviewOptions.withDefaults()
+	reviewPage := ReviewPage{PackageName: packageName, Language: options.Language, Country: options.Country, Sort: reviewOptions.Sort, Reviews: []Review{}}
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	poster, isPoster := options.Fetcher.(Poster)
+	if !isPoster {
+		return reviewPage, errors.New(errorFetcherCannotPost)
+	}
+	rpcURL := options.batchExecuteURL(reviewRPCID)
+
+	token := reviewOptions.ContinuationToken
+	for len(reviewPage.Reviews) < reviewOptions.Limit {
+		count := reviewOptions.Limit - len(reviewPage.Reviews)
+		if count > reviewsPerRequest {
+			count = reviewsPerRequest
+		}
+
+		response, postError := poster.Post(ctx, rpcURL, getReviewRequest(packageName, reviewOptions, count, token))
+		if postError != nil {
+			return reviewPage, &CrawlError{Err: getContextError(ctx, ErrUpstreamUnavailable), PackageName: packageName, URL: rpcURL, Detail: postError.Error()}
+		}
+		if response.StatusCode != http.StatusOK {
+			return reviewPage, &CrawlError{Err: errorFromStatusCode(response.StatusCode), PackageName: packageName, URL: rpcURL, StatusCode: response.StatusCode, RetryAfter: response.Header.Get("Retry-After")}
+		}
+		reviews, nextToken, parseError := parseReviewResponse(response.Body)
+		if parseError != nil {
+			return reviewPage, &CrawlError{Err: ErrLayoutChanged, PackageName: packageName, URL: rpcURL, Detail: parseError.Error()}
+		}
+
+		reviewPage.Reviews = append(reviewPage.Reviews, reviews...)
+		reviewPage.ContinuationToken = nextToken
+		token = nextToken
+		if token == "" || len(reviews) == 0 {
+			break
+		}
+	}
+
+	return reviewPage, nil
+}
+
+// returns the form requesting the next reviews from the rpc
+func getReviewRequest(packageName string, reviewOptions ReviewOptions, count int, token string) url.Values {
+	var tokenParameter interface{}
+	if token != "" {
+		tokenParameter = token
+	}
+	filter := []interface{}{}
+	if reviewOptions.Rating > 0 {
+		filter = []interface{}{nil, reviewOptions.Rating}
+	}
+	parameters := []interface{}{nil, nil, []interface{}{2, reviewSortIDs[reviewOptions.Sort], []interface{}{count, nil, tokenParameter}, nil, filter}, []interface{}{packageName, 7}}
+
+	return getBatchExecuteRequest(reviewRPCID, parameters)
+}
+
+// returns the url of the rpc in the storefront of the options
+func (options CrawlOptions) batchExecuteURL(rpcID string) string {
+	rpcURL := baseURLBatchExecute + "?rpcids=" + rpcID + "&hl=" + url.QueryEscape(options.Language)
+	if options.Country != "" {
+		rpcURL += "&gl=" + url.QueryEscape(options.Country)
+	}
+
+	return rpcURL
+}
+
+// returns the form calling the rpc with the parameters
+func getBatchExecuteRequest(rpcID string, parameters interface{}) url.Values {
+	parametersJSON, _ := json.Marshal(parameters)
+	request, _ := json.Marshal([]interface{}{[]interface{}{[]interface{}{rpcID, string(parametersJSON), nil, "generic"}}})
+
+	return url.Values{"f.req": []string{string(request)}}
+}
+
+// returns the data the rpc answered with, nil if it answered without data
+func parseBatchExecuteResponse(body string, rpcID string) ([]interface{}, error) {
+	body = strings.TrimPrefix(strings.TrimSpace(body), batchExecutePrefix)
+	listPosition := strings.Index(body, "[")
+	if listPosition < 0 {
+		return nil, errors.New("it doesn't contain a list")
+	}
+	var envelopes []interface{}
+	decodeError := json.NewDecoder(strings.NewReader(body[listPosition:])).Decode(&envelopes)
+	if decodeError != nil {
+		return nil, decodeError
+	}
+
+	for _, envelope := range envelopes {
+		envelopeList, isList := envelope.([]interface{})
+		if envelopeRPCID, _ := getInitDataValue(envelopeList, []int{1}).(string); !isList || envelopeRPCID != rpcID {
+			continue
+		}
+		payload, isString := getInitDataValue(envelopeList, []int{2}).(string)
+		if !isString {
+			return nil, nil
+		}
+		var data []interface{}
+		decodeError = json.Unmarshal([]byte(payload), &data)
+		if decodeError != nil {
+			return nil, decodeError
+		}
+		return data, nil
+	}
+
+	return nil, errors.New("it doesn't contain the result of rpc \"" + rpcID + "\"")
+}
+
+// returns the reviews and the token for the following reviews from the response of the rpc
+func parseReviewResponse(body string) ([]Review, string, error) {
+	reviews := []Review{}
+	// the rpc answers without data if there are no reviews left
+	data, parseError := parseBatchExecuteResponse(body, reviewRPCID)
+	if parseError != nil {
+		return reviews, "", errors.New(errorReviewResponse + " : " + parseError.Error())
+	}
+
+	entries, _ := getInitDataValue(data, []int{0}).([]interface{})
+	for _, entry := range entries {
+		if entryList, isList := entry.([]interface{}); isList {
+			reviews = append(reviews, getReview(entryList))
+		}
+	}
+	token, _ := getInitDataValue(data, []int{1, 1}).(string)
+
+	return reviews, token, nil
+}
+
+// returns the review of an entry of the rpc response
+func getReview(entry []interface{}) Review {
+	var review Review
+	review.ReviewID, _ = getInitDataValue(entry, reviewPathID).(string)
+	review.Author, _ = getInitDataValue(entry, reviewPathAuthor).(string)
+	review.Text, _ = getInitDataValue(entry, reviewPathText).(string)
+	review.AppVersion, _ = getInitDataValue(entry, reviewPathAppVersion).(string)
+	if rating, isNumber := getJSONNumber(getInitDataValue(entry, reviewPathRating)); isNumber {
+		review.Rating = int(rating)
+	}
+	if date, isNumber := getJSONNumber(getInitDataValue(entry, reviewPathDate)); isNumber {
+		review.Date = getUnixTime(date)
+	}
+	if thumbsUpCount, isNumber := getJSONNumber(getInitDataValue(entry, reviewPathThumbsUpCount)); isNumber {
+		review.ThumbsUpCount = int64(thumbsUpCount)
+	}
+	if getInitDataValue(entry, reviewPathReply) != nil {
+		reply := &DeveloperReply{}
+		reply.Text, _ = getInitDataValue(entry, reviewPathReplyText).(string)
+		if date, isNumber := getJSONNumber(getInitDataValue(entry, reviewPathReplyDate)); isNumber {
+			reply.Date = getUnixTime(date)
+		}
+		review.DeveloperReply = reply
+	}
+
+	return review
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// reviewFetcher answers the review rpc with numbered reviews, "total" reviews are available in pages of the requested size.
+// The responses are built like the parser expects them, TestParseCapturedReviewResponse checks a captured one
+type reviewFetcher struct {
+	total    int
+	requests []url.Values
+}
+
+func (fetcher *reviewFetcher) Fetch(ctx context.Context, url string) (FetchResponse, error) {
+	return FetchResponse{StatusCode: http.StatusNotFound}, nil
+}
+
+func (fetcher *reviewFetcher) Post(ctx context.Context, url string, form url.Values) (FetchResponse, error) {
+	fetcher.requests = append(fetcher.requests, form)
+
+	var request [][][]interface{}
+	json.Unmarshal([]byte(form.Get("f.req")), &request)
+	var parameters []interface{}
+	json.Unmarshal([]byte(request[0][0][1].(string)), &parameters)
+	count := int(getInitDataValue(parameters, []int{2, 2, 0}).(float64))
+	offset := 0
+	if token, isString := getInitDataValue(parameters, []int{2, 2, 2}).(string); isString {
+		offset, _ = strconv.Atoi(token)
+	}
+
+	var entries []interface{}
+	for position := offset; position < offset+count && position < fetcher.total; position++ {
+		entry := []interface{}{"gp:" + strconv.Itoa(position), []interface{}{"Author " + strconv.Itoa(position)}, 5, nil, "Great app", []interface{}{1546300800, 0}, 3, nil, nil, nil, "2.18.1"}
+		if position == 0 {
+			entry[7] = []interface{}{nil, "Thank you!", []interface{}{1546387200, 0}}
+		}
+		entries = append(entries, entry)
+	}
+	var nextToken interface{}
+	if offset+count < fetcher.total {
+		nextToken = strconv.Itoa(offset + count)
+	}
+	payload, _ := json.Marshal([]interface{}{entries, []interface{}{nil, nextToken}})
+	body, _ := json.Marshal([]interface{}{[]interface{}{"wrb.fr", reviewRPCID, string(payload), nil, nil, nil, "generic"}, []interface{}{"di", 42}})
+
+	return FetchResponse{StatusCode: http.StatusOK, Body: batchExecutePrefix + "\n\n" + string(body)}, nil
+}
+
+func TestCrawlReviews(t *testing.T) {
+	fetcher := &reviewFetcher{total: 150}
+	reviewPage, err := CrawlReviews(context.Background(), "com.whatsapp", ReviewOptions{Limit: 120, Sort: ReviewSortRating, Rating: 5}, CrawlOptions{Fetcher: fetcher})
+	if err != nil {
+		t.Fatalf("crawl should succeed : %v", err)
+	}
+	if len(reviewPage.Reviews) != 120 || len(fetcher.requests) != 2 || reviewPage.ContinuationToken != "120" {
+		t.Errorf("120 reviews should be crawled with 2 requests, got %d reviews with %d requests and token %q", len(reviewPage.Reviews), len(fetcher.requests), reviewPage.ContinuationToken)
+	}
+	wantRequest := `[[["UsvDTd","[null,null,[2,3,[100,null,null],null,[null,5]],[\"com.whatsapp\",7]]",null,"generic"]]]`
+	if request := fetcher.requests[0].Get("f.req"); request != wantRequest {
+		t.Errorf("request should be %s, got %s", wantRequest, request)
+	}
+
+	review := reviewPage.Reviews[0]
+	wantReview := Review{ReviewID: "gp:0", Author: "Author 0", Rating: 5, Text: "Great app", Date: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), ThumbsUpCount: 3, AppVersion: "2.18.1", DeveloperReply: &DeveloperReply{Text: "Thank you!", Date: time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC)}}
+	if review.DeveloperReply == nil || *review.DeveloperReply != *wantReview.DeveloperReply {
+		t.Errorf("developer reply should be %+v, got %+v", wantReview.DeveloperReply, review.DeveloperReply)
+	}
+	review.DeveloperReply, wantReview.DeveloperReply = nil, nil
+	if review != wantReview {
+		t.Errorf("review should be %+v, got %+v", wantReview, review)
+	}
+	if reviewPage.Reviews[1].DeveloperReply != nil {
+		t.Errorf("review without reply should not have a developer reply")
+	}
+
+	// the continuation token continues with the remaining reviews
+	reviewPage, _ = CrawlReviews(context.Background(), "com.whatsapp", ReviewOptions{ContinuationToken: reviewPage.ContinuationToken}, CrawlOptions{Fetcher: fetcher})
+	if len(reviewPage.Reviews) != 30 || reviewPage.Reviews[0].ReviewID != "gp:120" || reviewPage.ContinuationToken != "" {
+		t.Errorf("remaining 30 reviews should be crawled, got %d reviews starting with %q", len(reviewPage.Reviews), reviewPage.Reviews[0].ReviewID)
+	}
+}
+
+func TestParseReviewResponse(t *testing.T) {
+	reviews, token, err := parseReviewResponse(batchExecutePrefix + "\n\n" + `[["wrb.fr","UsvDTd",null,null,null,[5],"generic"]]`)
+	if err != nil || len(reviews) != 0 || token != "" {
+		t.Errorf("response without data should not contain reviews, got %v", err)
+	}
+
+	if _, _, err := parseReviewResponse("<html>Error 400</html>"); err == nil {
+		t.Errorf("html should not be parsed as review response")
+	}
+	if _, err := CrawlReviews(context.Background(), "com.whatsapp", ReviewOptions{}, CrawlOptions{Fetcher: &reviewFetcherWithBody{"<html>Error</html>"}}); !errors.Is(err, ErrLayoutChanged) {
+		t.Errorf("unreadable response should be reported as layout change, got %v", err)
+	}
+}
+
+func TestParseCapturedReviewResponse(t *testing.T) {
+	path := filepath.Join("testdata", "reviews", "newest.txt")
+	if *capture {
+		captureReviewResponse(t, path, "com.whatsapp")
+	}
+	body, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Skip("there is no captured response of the review rpc, capture it with -capture")
+	}
+	if err != nil {
+		t.Fatalf("could not read captured response : %v", err)
+	}
+
+	reviews, token, err := parseReviewResponse(string(body))
+	if err != nil || len(reviews) == 0 || token == "" {
+		t.Fatalf("captured response should contain reviews and a token, got %d reviews, token %q and %v", len(reviews), token, err)
+	}
+	for _, review := range reviews {
+		if review.ReviewID == "" || review.Author == "" || review.Rating < 1 || review.Rating > 5 || review.Date.IsZero() {
+			t.Errorf("review should have id, author, rating and date, got %+v", review)
+		}
+	}
+}
+
+// saves the response of the review rpc for the newest reviews of the app as it is sent by the Google Play Store
+func captureReviewResponse(t *testing.T, path string, packageName string) {
+	options := CrawlOptions{}.withDefaults()
+	form := getReviewRequest(packageName, ReviewOptions{}.withDefaults(), 20, "")
+	response, err := NewHTTPFetcher().Post(context.Background(), options.batchExecuteURL(reviewRPCID), form)
+	if err != nil {
+		t.Fatalf("could not capture response of the review rpc : %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("could not capture response of the review rpc, got status %d", response.StatusCode)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("could not create directory of the response : %v", err)
+	}
+	if err := ioutil.WriteFile(path, []byte(response.Body), 0644); err != nil {
+		t.Fatalf("could not save response of the review rpc : %v", err)
+	}
+}
+
+// reviewFetcherWithBody answers every rpc with the body
+type reviewFetcherWithBody struct {
+	body string
+}
+
+func (fetcher *reviewFetcherWithBody) Fetch(ctx context.Context, url string) (FetchResponse, error) {
+	return FetchResponse{StatusCode: http.StatusNotFound}, nil
+}
+
+func (fetcher *reviewFetcherWithBody) Post(ctx context.Context, url string, form url.Values) (FetchResponse, error) {
+	return FetchResponse{StatusCode: http.StatusOK, Body: fetcher.body}, nil
+}
+
+func TestGetAppReviews(t *testing.T) {
+	defaultFetcher := pageFetcher
+	pageFetcher = &reviewFetcher{total: 3}
+	defer func() { pageFetcher = defaultFetcher }()
+
+	rr := executeRequest(buildRequest("GET", "/hitec/crawl/app-reviews/google-play/com.whatsapp?sort=most_relevant&limit=2", nil, t))
+	var reviewPage ReviewPage
+	json.NewDecoder(rr.Body).Decode(&reviewPage)
+	if rr.Code != http.StatusOK || len(reviewPage.Reviews) != 2 || reviewPage.Sort != ReviewSortMostRelevant {
+		t.Errorf("2 reviews should be returned, got status %d and %+v", rr.Code, reviewPage)
+	}
+
+	for _, query := range []string{"sort=oldest", "rating=6", "limit=0", "limit=many"} {
+		rr = executeRequest(buildRequest("GET", "/hitec/crawl/app-reviews/google-play/com.whatsapp?"+query, nil, t))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s should be rejected, got status %d", query, rr.Code)
+		}
+	}
+}
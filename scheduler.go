@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRecrawlIntervalSeconds = 24 * 60 * 60
+	defaultRecrawlJitterSeconds   = 60 * 60
+	// how often the scheduler looks for app pages which are due
+	schedulerTick = 10 * time.Second
+)
+
+// WatchlistEntry model, a package the scheduler recrawls regularly
+type WatchlistEntry struct {
+	PackageName string    `json:"package_name"`
+	NextCrawl   time.Time `json:"next_crawl"`
+	LastCrawl   time.Time `json:"last_crawl,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// Scheduler recrawls the app pages of all packages on the watchlist every interval, a random jitter spreads the crawls
+// so that they don't hit the Google Play Store at the same time
+type Scheduler struct {
+	interval time.Duration
+	jitter   time.Duration
+	options  CrawlOptions
+	// file the watchlist is read from and written to, the watchlist is only kept in memory if the path is empty
+	path string
+	// called with the result of every crawl
+	onResult func(appPage AppPage, err error)
+	now      func() time.Time
+	random   *rand.Rand
+
+	mutex     sync.Mutex
+	watchlist map[string]*WatchlistEntry
+}
+
+// NewScheduler returns a scheduler with the packages of the watchlist file, a missing file is an empty watchlist
+func NewScheduler(path string, interval time.Duration, jitter time.Duration, options CrawlOptions) (*Scheduler, error) {
+	scheduler := &Scheduler{
+		interval:  interval,
+		jitter:    jitter,
+		options:   options,
+		path:      path,
+		onResult:  logCrawlResult,
+		now:       time.Now,
+		random:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		watchlist: map[string]*WatchlistEntry{},
+	}
+	if path == "" {
+		return scheduler, nil
+	}
+
+	packageNames, readError := readWatchlist(path)
+	if readError != nil && !os.IsNotExist(readError) {
+		return nil, readError
+	}
+	for _, packageName := range packageNames {
+		scheduler.add(packageName)
+	}
+
+	return scheduler, nil
+}
+
+// reads the package names from the watchlist file, one per line, empty lines and lines starting with # are ignored
+func readWatchlist(path string) ([]string, error) {
+	file, openError := os.Open(path)
+	if openError != nil {
+		return nil, openError
+	}
+	defer file.Close()
+
+	var packageNames []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			packageNames = append(packageNames, line)
+		}
+	}
+
+	return packageNames, scanner.Err()
+}
+
+// Run crawls the due app pages until the channel is closed, closing it also cancels the running crawl
+func (scheduler *Scheduler) Run(stop <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(schedulerTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			scheduler.crawlDue(ctx)
+		}
+	}
+}
+
+// crawls all app pages which are due one after another and schedules their next crawl, the timeout of the options
+// applies to every crawl
+func (scheduler *Scheduler) crawlDue(ctx context.Context) {
+	for _, packageName := range scheduler.due() {
+		if ctx.Err() != nil {
+			return
+		}
+		appPage, _, crawlError := CrawlCached(ctx, appPageCache, packageName, scheduler.options, true)
+		if errors.Is(crawlError, ErrCanceled) {
+			// the scheduler was stopped, the package stays due
+			return
+		}
+
+		scheduler.mutex.Lock()
+		// the package could have been removed during the crawl
+		if entry, exists := scheduler.watchlist[packageName]; exists {
+			entry.LastCrawl = scheduler.now()
+			entry.NextCrawl = entry.LastCrawl.Add(scheduler.interval + scheduler.randomJitter())
+			entry.LastError = ""
+			if crawlError != nil {
+				entry.LastError = crawlError.Error()
+			}
+		}
+		scheduler.mutex.Unlock()
+
+		scheduler.onResult(appPage, crawlError)
+	}
+}
+
+// returns the packages whose next crawl is due
+func (scheduler *Scheduler) due() []string {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	var packageNames []string
+	now := scheduler.now()
+	for packageName, entry := range scheduler.watchlist {
+		if !entry.NextCrawl.After(now) {
+			packageNames = append(packageNames, packageName)
+		}
+	}
+	sort.Strings(packageNames)
+
+	return packageNames
+}
+
+// returns a random duration between zero and the jitter
+func (scheduler *Scheduler) randomJitter() time.Duration {
+	if scheduler.jitter <= 0 {
+		return 0
+	}
+
+	return time.Duration(scheduler.random.Int63n(int64(scheduler.jitter)))
+}
+
+// adds the package if it isn't on the watchlist yet, the first crawl happens within the jitter
+func (scheduler *Scheduler) add(packageName string) {
+	if _, exists := scheduler.watchlist[packageName]; !exists {
+		scheduler.watchlist[packageName] = &WatchlistEntry{PackageName: packageName, NextCrawl: scheduler.now().Add(scheduler.randomJitter())}
+	}
+}
+
+// Watchlist returns all entries of the watchlist ordered by package name
+func (scheduler *Scheduler) Watchlist() []WatchlistEntry {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	entries := []WatchlistEntry{}
+	for _, entry := range scheduler.watchlist {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].PackageName < entries[j].PackageName
+	})
+
+	return entries
+}
+
+// Add puts the packages on the watchlist and writes the watchlist file
+func (scheduler *Scheduler) Add(packageNames []string) error {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	for _, packageName := range packageNames {
+		scheduler.add(packageName)
+	}
+
+	return scheduler.write()
+}
+
+// Remove takes the package off the watchlist and writes the watchlist file, it returns false if the package wasn't on it
+func (scheduler *Scheduler) Remove(packageName string) (bool, error) {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	if _, exists := scheduler.watchlist[packageName]; !exists {
+		return false, nil
+	}
+	delete(scheduler.watchlist, packageName)
+
+	return true, scheduler.write()
+}
+
+// writes the package names of the watchlist to the file, one per line
+func (scheduler *Scheduler) write() error {
+	if scheduler.path == "" {
+		return nil
+	}
+
+	var packageNames []string
+	for packageName := range scheduler.watchlist {
+		packageNames = append(packageNames, packageName)
+	}
+	sort.Strings(packageNames)
+	writeError := ioutil.WriteFile(scheduler.path, []byte(strings.Join(packageNames, "\n")+"\n"), 0644)
+	if writeError != nil {
+		return errors.New("watchlist \"" + scheduler.path + "\" could not be written : " + writeError.Error())
+	}
+
+	return nil
+}
+
+// prints the outcome of a scheduled crawl, the app page itself is saved by the store of the crawl options
+func logCrawlResult(appPage AppPage, err error) {
+	if err != nil {
+		fmt.Println("scheduled crawl failed :", err)
+	} else {
+		fmt.Println("scheduled crawl of", appPage.PackageName, "succeeded")
+	}
+}
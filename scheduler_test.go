@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSchedulerCrawlDue(t *testing.T) {
+	directory, _ := ioutil.TempDir("", "watchlist")
+	defer os.RemoveAll(directory)
+	path := filepath.Join(directory, "watchlist.txt")
+	ioutil.WriteFile(path, []byte("# apps of the team\ncom.whatsapp\n\ncom.does.not.exists.122\n"), 0644)
+
+	fetcher := newTestFetcher(loadAppPageFixtures())
+	defer fetcher.Close()
+	store := NewMemoryStore()
+	scheduler, err := NewScheduler(path, time.Hour, time.Minute, CrawlOptions{Fetcher: fetcher, Store: store})
+	if err != nil {
+		t.Fatalf("scheduler should be created : %v", err)
+	}
+	// the watchlist file was read with the real clock, so the first crawls are due within a minute from now on
+	now := time.Now().Add(-time.Minute)
+	scheduler.now = func() time.Time { return now }
+	var results []string
+	scheduler.onResult = func(appPage AppPage, err error) {
+		results = append(results, appPage.PackageName)
+	}
+
+	// the first crawls are spread over the jitter
+	scheduler.crawlDue(context.Background())
+	if len(results) != 0 {
+		t.Errorf("no app page should be due before the jitter passed, got %v", results)
+	}
+	now = now.Add(2 * time.Minute)
+	scheduler.crawlDue(context.Background())
+	if len(results) != 2 {
+		t.Fatalf("both app pages should be crawled, got %v", results)
+	}
+	if appPages, _ := store.AppPages("com.whatsapp", "en", ""); len(appPages) != 1 {
+		t.Errorf("crawled app page should be stored")
+	}
+
+	watchlist := scheduler.Watchlist()
+	if watchlist[0].PackageName != "com.does.not.exists.122" || watchlist[0].LastError == "" {
+		t.Errorf("failed crawl should be reported in the watchlist, got %+v", watchlist[0])
+	}
+	nextCrawl := watchlist[1].NextCrawl
+	if nextCrawl.Before(now.Add(time.Hour)) || !nextCrawl.Before(now.Add(time.Hour+time.Minute)) {
+		t.Errorf("next crawl should be after the interval plus jitter, got %v", nextCrawl)
+	}
+
+	// nothing is due until the interval passed
+	scheduler.crawlDue(context.Background())
+	if len(results) != 2 {
+		t.Errorf("app pages should not be crawled again before the interval, got %v", results)
+	}
+
+	scheduler.Add([]string{"com.ustwo.monumentvalley"})
+	scheduler.Remove("com.does.not.exists.122")
+	written, _ := ioutil.ReadFile(path)
+	if string(written) != "com.ustwo.monumentvalley\ncom.whatsapp\n" {
+		t.Errorf("watchlist file should be updated, got %q", written)
+	}
+}
+
+func TestWatchlistEndpoints(t *testing.T) {
+	scheduler, _ := NewScheduler("", time.Hour, time.Minute, CrawlOptions{})
+	watchlistScheduler = scheduler
+	defer func() { watchlistScheduler = nil }()
+
+	rr := executeRequest(buildRequest("POST", "/hitec/crawl/watchlist", strings.NewReader(`["com.whatsapp", "com.ustwo.monumentvalley"]`), t))
+	var watchlist []WatchlistEntry
+	json.NewDecoder(rr.Body).Decode(&watchlist)
+	if rr.Code != http.StatusOK || len(watchlist) != 2 {
+		t.Errorf("packages should be added, got status %d and %+v", rr.Code, watchlist)
+	}
+
+	rr = executeRequest(buildRequest("DELETE", "/hitec/crawl/watchlist/com.whatsapp", nil, t))
+	if rr.Code != http.StatusOK || len(scheduler.Watchlist()) != 1 {
+		t.Errorf("package should be removed, got status %d", rr.Code)
+	}
+	rr = executeRequest(buildRequest("DELETE", "/hitec/crawl/watchlist/com.whatsapp", nil, t))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("package which is not on the watchlist should not be found, got status %d", rr.Code)
+	}
+
+	rr = executeRequest(buildRequest("GET", "/hitec/crawl/watchlist", nil, t))
+	watchlist = nil
+	json.NewDecoder(rr.Body).Decode(&watchlist)
+	if len(watchlist) != 1 || watchlist[0].PackageName != "com.ustwo.monumentvalley" {
+		t.Errorf("watchlist should contain com.ustwo.monumentvalley, got %+v", watchlist)
+	}
+}
+
+func TestSchedulerStop(t *testing.T) {
+	fetcher := newTestFetcher(loadAppPageFixtures())
+	defer fetcher.Close()
+	scheduler, _ := NewScheduler("", time.Hour, 0, CrawlOptions{Fetcher: fetcher})
+	scheduler.Add([]string{"com.whatsapp"})
+	var results []string
+	scheduler.onResult = func(appPage AppPage, err error) {
+		results = append(results, appPage.PackageName)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	scheduler.crawlDue(ctx)
+	if len(results) != 0 || !scheduler.Watchlist()[0].LastCrawl.IsZero() {
+		t.Errorf("a stopped scheduler should not crawl, got %v", results)
+	}
+
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		scheduler.Run(stop)
+		close(stopped)
+	}()
+	close(stop)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Errorf("scheduler should return once it is stopped")
+	}
+}
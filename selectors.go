@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync/atomic"
+	"syscall"
+)
+
+const (
+	defaultSelectorProfilePath = "selectors.json"
+)
+
+// SelectorProfile model, contains the CSS classes, itemprops and texts the getters use to find the elements of an
+// app page, so that a redesign of the Google Play Store can be handled without rebuilding the service
+type SelectorProfile struct {
+	Version string `json:"version"`
+
+	// CSS classes for finding the right elements
+	ClassMainInformationAppContainer        string `json:"class_main_information_app_container"`
+	ClassMainInformationSimilarContainer    string `json:"class_main_information_similar_container"`
+	ClassMainInformationApp                 string `json:"class_main_information_app"`
+	ClassMainInformationSimilar             string `json:"class_main_information_similar"`
+	ClassMainInformationHeadline            string `json:"class_main_information_headline"`
+	ClassMainInformationAdditionalContainer string `json:"class_main_information_additional_container"`
+	ClassAppPage                            string `json:"class_app_page"`
+	ClassAppCategoryUsk                     string `json:"class_app_category_usk"`
+	ClassAppRating                          string `json:"class_app_rating"`
+	ClassAppStarsCount                      string `json:"class_app_stars_count"`
+	ClassAppCountPerRating                  string `json:"class_app_count_per_rating"`
+	ClassAppContainsAds                     string `json:"class_app_contains_ads"`
+	ClassAppInAppPurchases                  string `json:"class_app_in_app_purchases"`
+	ClassDeveloperHeader                    string `json:"class_developer_header"`
+	ClassDeveloperDescription               string `json:"class_developer_description"`
+	ClassListCard                           string `json:"class_list_card"`
+	ClassListCardName                       string `json:"class_list_card_name"`
+	ClassListCardDeveloper                  string `json:"class_list_card_developer"`
+	ClassListCardPrice                      string `json:"class_list_card_price"`
+	ClassDataSafetySection                  string `json:"class_data_safety_section"`
+	ClassDataSafetyCategory                 string `json:"class_data_safety_category"`
+	ClassDataSafetyEntry                    string `json:"class_data_safety_entry"`
+	ClassDataSafetyType                     string `json:"class_data_safety_type"`
+	ClassDataSafetyPurposes                 string `json:"class_data_safety_purposes"`
+	ClassAppIcon                            string `json:"class_app_icon"`
+	ClassAppScreenshots                     string `json:"class_app_screenshots"`
+	ClassAppFeatureGraphic                  string `json:"class_app_feature_graphic"`
+	ClassAppPromoVideo                      string `json:"class_app_promo_video"`
+
+	// itemprop values
+	ItempropAppName         string `json:"itemprop_app_name"`
+	ItempropAppCategory     string `json:"itemprop_app_category"`
+	ItempropAppPrice        string `json:"itemprop_app_price"`
+	ItempropAppDescription  string `json:"itemprop_app_description"`
+	ItempropAppTopDeveloper string `json:"itemprop_app_top_developer"`
+
+	// element values as strings, written in english and matched in the language of the page through its locale
+	ValueContainsAds       string `json:"value_contains_ads"`
+	ValueInAppPurchases    string `json:"value_in_app_purchases"`
+	ValueRequiresOsVersion string `json:"value_requires_os_version"`
+}
+
+// profile used if no profile file is available, it matches the layout of the Google Play Store the crawler was written for
+var defaultSelectorProfile = SelectorProfile{
+	Version: "2018-11",
+
+	ClassMainInformationAppContainer:        "oQ6oV",
+	ClassMainInformationSimilarContainer:    "Ktdaqe",
+	ClassMainInformationApp:                 "rlnrKc",
+	ClassMainInformationSimilar:             "ZmHEEd",
+	ClassMainInformationHeadline:            "Rm6Gwb",
+	ClassMainInformationAdditionalContainer: "IxB2fe",
+	ClassAppPage:                            "LXrl4c",
+	ClassAppCategoryUsk:                     "ZVWMWc",
+	ClassAppRating:                          "BHMmbe",
+	ClassAppStarsCount:                      "EymY4b",
+	ClassAppCountPerRating:                  "VEF2C",
+	ClassAppContainsAds:                     "bSIuKf",
+	ClassAppInAppPurchases:                  "bSIuKf",
+	ClassDeveloperHeader:                    "B2j3Ud",
+	ClassDeveloperDescription:               "W4P4ne",
+	ClassListCard:                           "Vpfmgd",
+	ClassListCardName:                       "WsMG1c",
+	ClassListCardDeveloper:                  "KoLSrc",
+	ClassListCardPrice:                      "VfPpfd",
+	ClassDataSafetySection:                  "Mf2Txd",
+	ClassDataSafetyCategory:                 "Vwijed",
+	ClassDataSafetyEntry:                    "qcRj3b",
+	ClassDataSafetyType:                     "FnWDne",
+	ClassDataSafetyPurposes:                 "fozKzd",
+	ClassAppIcon:                            "sHb2Xb",
+	ClassAppScreenshots:                     "SgoUSc",
+	ClassAppFeatureGraphic:                  "MSLVtf",
+	ClassAppPromoVideo:                      "MMZjL",
+
+	ItempropAppName:         "name",
+	ItempropAppCategory:     "genre",
+	ItempropAppPrice:        "price",
+	ItempropAppDescription:  "description",
+	ItempropAppTopDeveloper: "editorsChoiceBadgeUrl",
+
+	ValueContainsAds:       "Contains Ads",
+	ValueInAppPurchases:    "Offers in-app purchases",
+	ValueRequiresOsVersion: "Varies with device",
+}
+
+// profile used by all crawls started from now on
+var activeSelectorProfile atomic.Value
+
+func init() {
+	activeSelectorProfile.Store(defaultSelectorProfile)
+}
+
+// returns the profile used by all crawls started from now on
+func getSelectorProfile() SelectorProfile {
+	return activeSelectorProfile.Load().(SelectorProfile)
+}
+
+// replaces the profile used by all crawls started from now on, crawls already running keep their profile
+func setSelectorProfile(profile SelectorProfile) {
+	activeSelectorProfile.Store(profile)
+}
+
+// reads and validates a selector profile from a json file, selectors the file doesn't contain are taken from the
+// built-in profile, so profiles written before a selector was added keep working. The version has to be given
+func loadSelectorProfile(path string) (SelectorProfile, error) {
+	profile := defaultSelectorProfile
+	profile.Version = ""
+
+	profileJSON, readError := ioutil.ReadFile(path)
+	if readError != nil {
+		return profile, readError
+	}
+	decodeError := json.Unmarshal(profileJSON, &profile)
+	if decodeError != nil {
+		return profile, errors.New("selector profile \"" + path + "\" is not valid json : " + decodeError.Error())
+	}
+	validationError := profile.validate()
+	if validationError != nil {
+		return profile, errors.New("selector profile \"" + path + "\" is not valid : " + validationError.Error())
+	}
+
+	return profile, nil
+}
+
+// loads the profile from the file and activates it, the active profile stays in use if the file is not valid
+func reloadSelectorProfile(path string) (SelectorProfile, error) {
+	profile, loadError := loadSelectorProfile(path)
+	if loadError != nil {
+		return getSelectorProfile(), loadError
+	}
+	setSelectorProfile(profile)
+
+	return profile, nil
+}
+
+// checks that the profile has a version and no empty selector
+func (profile SelectorProfile) validate() error {
+	profileValue := reflect.ValueOf(profile)
+	profileType := profileValue.Type()
+	for position := 0; position < profileType.NumField(); position++ {
+		if profileValue.Field(position).String() == "" {
+			return errors.New("\"" + profileType.Field(position).Tag.Get("json") + "\" should not be empty")
+		}
+	}
+
+	return nil
+}
+
+// reloads the profile from the file whenever the service receives SIGHUP
+func reloadSelectorProfileOnSignal(path string) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		for range signals {
+			profile, reloadError := reloadSelectorProfile(path)
+			if reloadError != nil {
+				fmt.Println("could not reload selector profile, keeping version", profile.Version, ":", reloadError)
+			} else {
+				fmt.Println("reloaded selector profile version", profile.Version)
+			}
+		}
+	}()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadSelectorProfile(t *testing.T) {
+	profile, err := loadSelectorProfile(defaultSelectorProfilePath)
+	if err != nil {
+		t.Fatalf("%s should be valid : %v", defaultSelectorProfilePath, err)
+	}
+	if !reflect.DeepEqual(profile, defaultSelectorProfile) {
+		t.Errorf("%s should match the built-in profile", defaultSelectorProfilePath)
+	}
+}
+
+func TestReloadSelectorProfile(t *testing.T) {
+	defer setSelectorProfile(defaultSelectorProfile)
+	directory, err := ioutil.TempDir("", "selectors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	path := filepath.Join(directory, "selectors.json")
+
+	// a profile with an empty selector is rejected and the active profile stays in use
+	ioutil.WriteFile(path, []byte(`{"version": "2019-01", "class_app_page": ""}`), 0644)
+	profile, err := reloadSelectorProfile(path)
+	if err == nil || !strings.Contains(err.Error(), "should not be empty") {
+		t.Errorf("profile with an empty selector should be rejected, got %v", err)
+	}
+	if profile.Version != defaultSelectorProfile.Version || getSelectorProfile().Version != defaultSelectorProfile.Version {
+		t.Errorf("active profile should still be %s", defaultSelectorProfile.Version)
+	}
+	ioutil.WriteFile(path, []byte(`{"class_app_page": "xyz"}`), 0644)
+	if _, err = reloadSelectorProfile(path); err == nil || !strings.Contains(err.Error(), "\"version\" should not be empty") {
+		t.Errorf("profile without a version should be rejected, got %v", err)
+	}
+
+	// selectors missing in a profile written before they were added are taken from the built-in profile
+	ioutil.WriteFile(path, []byte(`{"version": "2019-01", "class_app_page": "xyz"}`), 0644)
+	profile, err = loadSelectorProfile(path)
+	if err != nil || profile.ClassAppPage != "xyz" || profile.ClassAppIcon != defaultSelectorProfile.ClassAppIcon {
+		t.Errorf("missing selectors should be taken from the built-in profile, got %v", err)
+	}
+
+	// a complete profile is activated and used by the crawler
+	profileJSON, _ := ioutil.ReadFile(defaultSelectorProfilePath)
+	profileJSON = []byte(strings.Replace(string(profileJSON), `"version": "2018-11"`, `"version": "2019-01"`, 1))
+	profileJSON = []byte(strings.Replace(string(profileJSON), `"class_app_rating": "BHMmbe"`, `"class_app_rating": "pf5lIe"`, 1))
+	ioutil.WriteFile(path, profileJSON, 0644)
+	if _, err := reloadSelectorProfile(path); err != nil {
+		t.Fatalf("profile should be reloaded : %v", err)
+	}
+	if getSelectorProfile().ClassAppRating != "pf5lIe" {
+		t.Errorf("reloaded profile should be active")
+	}
+
+	appPage := crawlAppPage(context.Background(), parseDoc(readFixture(t, "app-pages", "free")), "com.whatsapp", CrawlOptions{Fetcher: NewHTTPFetcher()}.withDefaults())
+	if appPage.SelectorProfileVersion != "2019-01" {
+		t.Errorf("app page should report profile version 2019-01, got %q", appPage.SelectorProfileVersion)
+	}
+	if appPage.FieldSources[fieldRating] == strategyNameDOM {
+		t.Errorf("rating should not be found in the html with the changed CSS class")
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSimilarGraphDepth    = 1
+	maxSimilarGraphDepth        = 3
+	defaultSimilarGraphMaxNodes = 50
+	maxSimilarGraphMaxNodes     = 500
+	// the whole graph is crawled within this time, the timeout of the crawl options only applies to single app pages
+	defaultSimilarGraphTimeoutSeconds = 120
+
+	// export formats of the graph
+	SimilarGraphFormatJSON    = "json"
+	SimilarGraphFormatGraphML = "graphml"
+	SimilarGraphFormatDOT     = "dot"
+)
+
+// SimilarGraph model, the apps reachable over the "Similar apps" blocks starting at one app
+type SimilarGraph struct {
+	PackageName string             `json:"package_name"`
+	Language    string             `json:"language"`
+	Country     string             `json:"country"`
+	Depth       int                `json:"depth"`
+	Nodes       []SimilarGraphNode `json:"nodes"`
+	Edges       []SimilarGraphEdge `json:"edges"`
+	// true if more apps were linked than the maximum number of nodes allowed or the timeout passed before all apps were
+	// crawled
+	Truncated bool `json:"truncated"`
+}
+
+// SimilarGraphNode model, an app of the graph with the basic information of its app page
+type SimilarGraphNode struct {
+	PackageName string `json:"package_name"`
+	// number of links between the app and the start of the graph
+	Depth         int      `json:"depth"`
+	Name          string   `json:"name"`
+	DeveloperID   string   `json:"developer_id"`
+	Category      string   `json:"category"`
+	Rating        float64  `json:"rating"`
+	StarsCount    int64    `json:"stars_count"`
+	InstallBucket string   `json:"install_bucket"`
+	Errors        []string `json:"errors"`
+}
+
+// SimilarGraphEdge model, the app page of "from" lists "to" as similar app
+type SimilarGraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// SimilarGraphOptions model, limits how far the graph is crawled
+type SimilarGraphOptions struct {
+	// number of links followed from the start
+	Depth    int
+	MaxNodes int
+	// deadline of the whole graph, zero doesn't limit it
+	Timeout time.Duration
+}
+
+// fills the options which were not set with their defaults
+func (graphOptions SimilarGraphOptions) withDefaults() SimilarGraphOptions {
+	if graphOptions.Depth <= 0 {
+		graphOptions.Depth = defaultSimilarGraphDepth
+	}
+	if graphOptions.MaxNodes <= 0 {
+		graphOptions.MaxNodes = defaultSimilarGraphMaxNodes
+	}
+
+	return graphOptions
+}
+
+// CrawlSimilarGraph walks the similar apps breadth first, every app is crawled once and at most "concurrency" app
+// pages are crawled at the same time, all requests share the rate limiter of the fetcher. Only the error of the start
+// is returned, it wraps the same errors as CrawlContext, failures of the other apps are reported in their nodes. Once
+// the timeout of the graph passed, the apps crawled so far are returned as truncated graph
+func CrawlSimilarGraph(ctx context.Context, cache *AppPageCache, packageName string, graphOptions SimilarGraphOptions, options CrawlOptions, concurrency int) (SimilarGraph, error) {
+	options = options.withDefaults()
+	graphOptions = graphOptions.withDefaults()
+	if graphOptions.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, graphOptions.Timeout)
+		defer cancel()
+	}
+	graph := SimilarGraph{PackageName: packageName, Language: options.Language, Country: options.Country, Depth: graphOptions.Depth, Nodes: []SimilarGraphNode{}, Edges: []SimilarGraphEdge{}}
+
+	startPage, _, crawlError := CrawlCached(ctx, cache, packageName, options, false)
+	if crawlError != nil {
+		return graph, crawlError
+	}
+	graph.Nodes = append(graph.Nodes, getSimilarGraphNode(startPage, 0))
+	known := map[string]bool{packageName: true}
+	knownEdges := map[SimilarGraphEdge]bool{}
+	addEdge := func(from string, to string) {
+		edge := SimilarGraphEdge{From: from, To: to}
+		if from != to && !knownEdges[edge] {
+			knownEdges[edge] = true
+			graph.Edges = append(graph.Edges, edge)
+		}
+	}
+
+	level := []AppPage{startPage}
+	for depth := 1; depth <= graphOptions.Depth && len(level) > 0 && ctx.Err() == nil; depth++ {
+		var nextPackageNames []string
+		for _, appPage := range level {
+			for _, similarApp := range appPage.SimilarApps {
+				if !known[similarApp] {
+					if len(graph.Nodes)+len(nextPackageNames) >= graphOptions.MaxNodes {
+						graph.Truncated = true
+						continue
+					}
+					known[similarApp] = true
+					nextPackageNames = append(nextPackageNames, similarApp)
+				}
+				addEdge(appPage.PackageName, similarApp)
+			}
+		}
+
+		level = crawlSimilarGraphLevel(ctx, cache, nextPackageNames, options, concurrency)
+		for _, appPage := range level {
+			graph.Nodes = append(graph.Nodes, getSimilarGraphNode(appPage, depth))
+		}
+	}
+	// the apps which were not crawled in time are part of the graph with the timeout in their errors
+	if ctx.Err() != nil {
+		graph.Truncated = true
+	}
+	// the apps of the last level may still link to each other or to apps closer to the start
+	for _, appPage := range level {
+		for _, similarApp := range appPage.SimilarApps {
+			if known[similarApp] {
+				addEdge(appPage.PackageName, similarApp)
+			}
+		}
+	}
+
+	return graph, nil
+}
+
+// crawls the app pages of one level of the graph, failures are reported in the app pages like in CrawlBatch
+func crawlSimilarGraphLevel(ctx context.Context, cache *AppPageCache, packageNames []string, options CrawlOptions, concurrency int) []AppPage {
+	if cache == nil {
+		return CrawlBatch(ctx, packageNames, options, concurrency)
+	}
+
+	return crawlConcurrently(ctx, packageNames, options, concurrency, func(ctx context.Context, packageName string, options CrawlOptions) (AppPage, error) {
+		appPage, _, crawlError := CrawlCached(ctx, cache, packageName, options, false)
+		return appPage, crawlError
+	})
+}
+
+// returns the node of the app page
+func getSimilarGraphNode(appPage AppPage, depth int) SimilarGraphNode {
+	return SimilarGraphNode{
+		PackageName:   appPage.PackageName,
+		Depth:         depth,
+		Name:          appPage.Name,
+		DeveloperID:   appPage.DeveloperID,
+		Category:      appPage.Category,
+		Rating:        appPage.Rating,
+		StarsCount:    appPage.StarsCount,
+		InstallBucket: appPage.InstallBucket,
+		Errors:        appPage.Errors,
+	}
+}
+
+// GraphML returns the graph in the GraphML format, the package names are the ids of the nodes
+func (graph SimilarGraph) GraphML() string {
+	var builder strings.Builder
+	builder.WriteString(xml.Header)
+	builder.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	builder.WriteString(`  <key id="name" for="node" attr.name="name" attr.type="string"/>` + "\n")
+	builder.WriteString(`  <key id="depth" for="node" attr.name="depth" attr.type="int"/>` + "\n")
+	builder.WriteString(`  <key id="developer_id" for="node" attr.name="developer_id" attr.type="string"/>` + "\n")
+	builder.WriteString(`  <key id="category" for="node" attr.name="category" attr.type="string"/>` + "\n")
+	builder.WriteString(`  <key id="rating" for="node" attr.name="rating" attr.type="double"/>` + "\n")
+	builder.WriteString(`  <key id="stars_count" for="node" attr.name="stars_count" attr.type="long"/>` + "\n")
+	builder.WriteString(`  <key id="install_bucket" for="node" attr.name="install_bucket" attr.type="string"/>` + "\n")
+	builder.WriteString(`  <graph id="` + escapeXML(graph.PackageName) + `" edgedefault="directed">` + "\n")
+	for _, node := range graph.Nodes {
+		builder.WriteString(`    <node id="` + escapeXML(node.PackageName) + `">` + "\n")
+		writeGraphMLData(&builder, "name", node.Name)
+		writeGraphMLData(&builder, "depth", strconv.Itoa(node.Depth))
+		writeGraphMLData(&builder, "developer_id", node.DeveloperID)
+		writeGraphMLData(&builder, "category", node.Category)
+		writeGraphMLData(&builder, "rating", strconv.FormatFloat(node.Rating, 'f', -1, 64))
+		writeGraphMLData(&builder, "stars_count", strconv.FormatInt(node.StarsCount, 10))
+		writeGraphMLData(&builder, "install_bucket", node.InstallBucket)
+		builder.WriteString("    </node>\n")
+	}
+	for _, edge := range graph.Edges {
+		builder.WriteString(`    <edge source="` + escapeXML(edge.From) + `" target="` + escapeXML(edge.To) + `"/>` + "\n")
+	}
+	builder.WriteString("  </graph>\n</graphml>\n")
+
+	return builder.String()
+}
+
+// writes the value of a node attribute, empty values are left out
+func writeGraphMLData(builder *strings.Builder, key string, value string) {
+	if value != "" {
+		builder.WriteString(`      <data key="` + key + `">` + escapeXML(value) + "</data>\n")
+	}
+}
+
+// returns the text with the special characters of xml escaped
+func escapeXML(text string) string {
+	var builder strings.Builder
+	xml.EscapeText(&builder, []byte(text))
+
+	return builder.String()
+}
+
+// DOT returns the graph in the DOT format of Graphviz, the nodes are labeled with the names of the apps
+func (graph SimilarGraph) DOT() string {
+	var builder strings.Builder
+	builder.WriteString("digraph " + strconv.Quote(graph.PackageName) + " {\n")
+	for _, node := range graph.Nodes {
+		label := node.Name
+		if label == "" {
+			label = node.PackageName
+		}
+		builder.WriteString("  " + strconv.Quote(node.PackageName) + " [label=" + strconv.Quote(label) + ", depth=" + strconv.Itoa(node.Depth) + "];\n")
+	}
+	for _, edge := range graph.Edges {
+		builder.WriteString("  " + strconv.Quote(edge.From) + " -> " + strconv.Quote(edge.To) + ";\n")
+	}
+	builder.WriteString("}\n")
+
+	return builder.String()
+}
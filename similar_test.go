@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// returns app pages linking to each other, com.viber.voip is missing so that its crawl fails
+func loadSimilarGraphFixtures(t *testing.T) map[string]string {
+	return map[string]string{
+		"com.whatsapp":           readFixture(t, "app-pages", "free"),
+		"org.telegram.messenger": readFixture(t, "app-pages", "free"),
+		"com.facebook.orca":      readFixture(t, "app-pages", "ads-iap"),
+	}
+}
+
+func TestCrawlSimilarGraph(t *testing.T) {
+	fetcher := newTestFetcher(loadSimilarGraphFixtures(t))
+	defer fetcher.Close()
+
+	graph, err := CrawlSimilarGraph(context.Background(), nil, "com.whatsapp", SimilarGraphOptions{Depth: 1}, CrawlOptions{Fetcher: fetcher}, 2)
+	if err != nil {
+		t.Fatalf("graph should be crawled, got %v", err)
+	}
+
+	depths := map[string]int{}
+	for _, node := range graph.Nodes {
+		depths[node.PackageName] = node.Depth
+		if node.PackageName == "com.viber.voip" && len(node.Errors) == 0 {
+			t.Errorf("failed crawl should be reported in the node")
+		}
+	}
+	wantDepths := map[string]int{"com.whatsapp": 0, "org.telegram.messenger": 1, "com.facebook.orca": 1, "com.viber.voip": 1}
+	if !reflect.DeepEqual(depths, wantDepths) {
+		t.Errorf("unexpected nodes %v", depths)
+	}
+	wantEdges := []SimilarGraphEdge{
+		{"com.whatsapp", "org.telegram.messenger"},
+		{"com.whatsapp", "com.facebook.orca"},
+		{"com.whatsapp", "com.viber.voip"},
+		{"org.telegram.messenger", "com.facebook.orca"},
+		{"org.telegram.messenger", "com.viber.voip"},
+	}
+	if !reflect.DeepEqual(graph.Edges, wantEdges) {
+		t.Errorf("edges should only lead to nodes of the graph, got %v", graph.Edges)
+	}
+	if graph.Truncated || graph.Nodes[0].Name != "WhatsApp Messenger" {
+		t.Errorf("unexpected graph %+v", graph)
+	}
+}
+
+func TestCrawlSimilarGraphMaxNodes(t *testing.T) {
+	fetcher := newTestFetcher(loadSimilarGraphFixtures(t))
+	defer fetcher.Close()
+
+	graph, err := CrawlSimilarGraph(context.Background(), nil, "com.whatsapp", SimilarGraphOptions{Depth: 2, MaxNodes: 5}, CrawlOptions{Fetcher: fetcher}, 2)
+	if err != nil {
+		t.Fatalf("graph should be crawled, got %v", err)
+	}
+	if len(graph.Nodes) != 5 || !graph.Truncated {
+		t.Errorf("graph should be cut off after 5 nodes, got %d nodes", len(graph.Nodes))
+	}
+	if last := graph.Nodes[len(graph.Nodes)-1]; last.PackageName != "com.king.candycrushsodasaga" || last.Depth != 2 {
+		t.Errorf("unexpected last node %+v", last)
+	}
+	requested := map[string]bool{}
+	for _, uri := range fetcher.Requested() {
+		if !strings.HasPrefix(uri, pathAppPage) {
+			continue
+		}
+		if requested[uri] {
+			t.Errorf("every app should only be requested once, %s was requested again", uri)
+		}
+		requested[uri] = true
+	}
+}
+
+// blockingFetcher answers all requests except the ones for the app pages of other apps than the start of the graph,
+// which are blocked until the context is done
+type blockingFetcher struct {
+	fetcher Fetcher
+	start   string
+}
+
+func (fetcher blockingFetcher) Fetch(ctx context.Context, url string) (FetchResponse, error) {
+	if !strings.Contains(url, pathAppPage) || strings.Contains(url, "id="+fetcher.start) {
+		return fetcher.fetcher.Fetch(ctx, url)
+	}
+	<-ctx.Done()
+
+	return FetchResponse{}, ctx.Err()
+}
+
+func TestCrawlSimilarGraphTimeout(t *testing.T) {
+	pages := newTestFetcher(loadSimilarGraphFixtures(t))
+	defer pages.Close()
+	fetcher := blockingFetcher{fetcher: pages, start: "com.whatsapp"}
+
+	graph, err := CrawlSimilarGraph(context.Background(), nil, "com.whatsapp", SimilarGraphOptions{Depth: 2, Timeout: 100 * time.Millisecond}, CrawlOptions{Fetcher: fetcher}, 2)
+	if err != nil {
+		t.Fatalf("graph should be returned once the timeout passed, got %v", err)
+	}
+	if !graph.Truncated || len(graph.Nodes) != 4 {
+		t.Errorf("graph should be truncated after the first level, got %d nodes", len(graph.Nodes))
+	}
+	if last := graph.Nodes[len(graph.Nodes)-1]; !strings.Contains(strings.Join(last.Errors, " "), ErrTimeout.Error()) {
+		t.Errorf("apps which were not crawled in time should report the timeout, got %v", last.Errors)
+	}
+}
+
+func TestSimilarGraphExport(t *testing.T) {
+	graph := SimilarGraph{
+		PackageName: "com.whatsapp",
+		Nodes: []SimilarGraphNode{
+			{PackageName: "com.whatsapp", Name: "WhatsApp Messenger"},
+			{PackageName: "com.facebook.orca", Depth: 1, Name: "Messenger – Text & \"Video\" Chat"},
+		},
+		Edges: []SimilarGraphEdge{{"com.whatsapp", "com.facebook.orca"}},
+	}
+
+	graphML := graph.GraphML()
+	for _, want := range []string{
+		`<node id="com.facebook.orca">`,
+		`<data key="name">Messenger – Text &amp; &#34;Video&#34; Chat</data>`,
+		`<edge source="com.whatsapp" target="com.facebook.orca"/>`,
+	} {
+		if !strings.Contains(graphML, want) {
+			t.Errorf("GraphML should contain %s, got\n%s", want, graphML)
+		}
+	}
+
+	dot := graph.DOT()
+	for _, want := range []string{
+		`"com.facebook.orca" [label="Messenger – Text & \"Video\" Chat", depth=1];`,
+		`"com.whatsapp" -> "com.facebook.orca";`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT should contain %s, got\n%s", want, dot)
+		}
+	}
+}
+
+func TestGetSimilarGraph(t *testing.T) {
+	rr := executeRequest(buildRequest("GET", "/hitec/crawl/similar-graph/google-play/com.whatsapp?depth=1&max_nodes=2", nil, t))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Status code differs. Expected %d .\n Got %d instead", http.StatusOK, rr.Code)
+	}
+	var graph SimilarGraph
+	if err := json.NewDecoder(rr.Body).Decode(&graph); err != nil || len(graph.Nodes) != 2 || !graph.Truncated {
+		t.Errorf("unexpected graph %+v (%v)", graph, err)
+	}
+
+	rr = executeRequest(buildRequest("GET", "/hitec/crawl/similar-graph/google-play/com.whatsapp?depth=1&max_nodes=1&format=dot", nil, t))
+	if rr.Code != http.StatusOK || rr.Header().Get("Content-Type") != "text/vnd.graphviz" || !strings.HasPrefix(rr.Body.String(), "digraph") {
+		t.Errorf("graph should be exported as DOT, got %d %s", rr.Code, rr.Body.String())
+	}
+
+	for _, query := range []string{"depth=4", "max_nodes=0", "format=svg"} {
+		rr = executeRequest(buildRequest("GET", "/hitec/crawl/similar-graph/google-play/com.whatsapp?"+query, nil, t))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s should be rejected, got %d", query, rr.Code)
+		}
+	}
+}
@@ -2,51 +2,656 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/gorilla/mux"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const (
-	requestError = "The request could not be recovered"
+	requestError       = "The request could not be recovered"
+	requestErrorLocale = "The parameters \"hl\" and \"gl\" should be language or country codes like \"en\", \"pt_BR\" or \"DE\""
+	requestErrorBatch  = "The request body should be a json array of package names"
+	requestErrorDiff   = "The parameters \"from\" and \"to\" should be dates like 20181231"
+	requestErrorWatch  = "The package is not on the watchlist"
+	requestErrorReview = "The parameter \"sort\" should be \"newest\", \"most_relevant\" or \"rating\", \"rating\" a number of stars from 1 to 5 and \"limit\" a number from 1 to "
+	requestErrorSearch = "The parameter \"q\" should not be empty"
+	requestErrorChart  = "The collection should be \"topselling_free\", \"topselling_paid\", \"topgrossing\", \"movers_shakers\", \"topselling_new_free\" or \"topselling_new_paid\" and the category \"all\" or a category like \"GAME_PUZZLE\""
+	requestErrorFormat = "The parameter \"format_version\" should be 1 for dates like 20181231 (deprecated) or 2 for timestamps"
+	requestErrorMedia  = "The parameter \"download_media\" requires the media directory MEDIA_DIR to be configured"
+	requestErrorGraph  = "The parameter \"format\" should be \"json\", \"graphml\" or \"dot\", \"depth\" a number from 1 to %d and \"max_nodes\" a number from 1 to %d"
 )
 
+// fetcher used for all outgoing requests to the Google Play Store
+var pageFetcher Fetcher = NewHTTPFetcher()
+
+// maximum number of app pages crawled at the same time for a batch request
+var batchConcurrency = defaultBatchConcurrency
+
+// file the selector profile is loaded from at startup and on reload
+var selectorProfilePath = defaultSelectorProfilePath
+
+// cache in front of the crawler for single app pages, nil disables caching
+var appPageCache *AppPageCache
+
+// store all crawled app pages are saved in, nil disables storing them
+var appPageStore Store
+
+// recrawls the packages on the watchlist regularly
+var watchlistScheduler *Scheduler
+
+// maximum duration of a single crawl requested via the API, zero doesn't limit it
+var crawlTimeout time.Duration
+
+// deadline of a whole similar graph, which crawls many app pages
+var similarGraphTimeout time.Duration
+
+// directory the images of app pages are downloaded into on request, empty disables downloading them
+var mediaDirectory string
+
 func main() {
+	batchConcurrency = getEnvInt("BATCH_CONCURRENCY", defaultBatchConcurrency)
+	crawlTimeout = time.Duration(getEnvInt("CRAWL_TIMEOUT", defaultCrawlTimeoutSeconds)) * time.Second
+	similarGraphTimeout = time.Duration(getEnvInt("SIMILAR_GRAPH_TIMEOUT", defaultSimilarGraphTimeoutSeconds)) * time.Second
+
+	// all requests to the Google Play Store share the rate limiter, including the ones for similar apps
+	limiter := NewHostRateLimiter(getEnvInt("RATE_LIMIT_PER_MINUTE", defaultRateLimitPerMinute), getEnvInt("RATE_LIMIT_BURST", defaultRateLimitBurst))
+	backoff := time.Duration(getEnvInt("FETCH_BACKOFF_MS", defaultFetchBackoffMillis)) * time.Millisecond
+	maxBackoff := time.Duration(getEnvInt("FETCH_MAX_BACKOFF_MS", defaultFetchMaxBackoffMillis)) * time.Millisecond
+	pageFetcher = NewRetryFetcher(NewHTTPFetcher(), getEnvNonNegativeInt("FETCH_RETRIES", defaultFetchRetries), backoff, maxBackoff, limiter)
+
+	if path := os.Getenv("SELECTOR_PROFILE"); path != "" {
+		selectorProfilePath = path
+	}
+	profile, profileError := reloadSelectorProfile(selectorProfilePath)
+	if profileError != nil {
+		fmt.Println("could not load selector profile, using built-in version", profile.Version, ":", profileError)
+	}
+	reloadSelectorProfileOnSignal(selectorProfilePath)
+
+	mediaDirectory = os.Getenv("MEDIA_DIR")
+
+	cacheTTL := time.Duration(getEnvInt("CACHE_TTL", defaultCacheTTLSeconds)) * time.Second
+	cache, cacheError := NewAppPageCache(getEnvInt("CACHE_SIZE", defaultCacheSize), cacheTTL, os.Getenv("CACHE_DIR"))
+	if cacheError != nil {
+		log.Fatal("could not create cache directory : ", cacheError)
+	}
+	appPageCache = cache
+
+	if mongoURL := os.Getenv("MONGO_URL"); mongoURL != "" {
+		mongoDatabase := os.Getenv("MONGO_DATABASE")
+		if mongoDatabase == "" {
+			mongoDatabase = defaultMongoDatabase
+		}
+		store, storeError := NewMongoStore(mongoURL, mongoDatabase)
+		if storeError != nil {
+			log.Fatal("could not connect to MongoDB : ", storeError)
+		}
+		appPageStore = store
+	}
+
+	recrawlInterval := time.Duration(getEnvInt("RECRAWL_INTERVAL", defaultRecrawlIntervalSeconds)) * time.Second
+	recrawlJitter := time.Duration(getEnvNonNegativeInt("RECRAWL_JITTER", defaultRecrawlJitterSeconds)) * time.Second
+	scheduler, schedulerError := NewScheduler(os.Getenv("WATCHLIST"), recrawlInterval, recrawlJitter, CrawlOptions{Fetcher: pageFetcher, Store: appPageStore, Timeout: crawlTimeout})
+	if schedulerError != nil {
+		log.Fatal("could not read watchlist : ", schedulerError)
+	}
+	watchlistScheduler = scheduler
+	go watchlistScheduler.Run(make(chan struct{}))
+
 	log.Fatal(http.ListenAndServe(":9622", makeRouter()))
 }
 
 func makeRouter() *mux.Router {
 	router := mux.NewRouter()
 	router.HandleFunc("/hitec/crawl/app-page/google-play/{package_name}", getAppPage).Methods("GET")
+	router.HandleFunc("/hitec/crawl/app-page/google-play", postAppPages).Methods("POST")
+	router.HandleFunc("/hitec/crawl/app-reviews/google-play/{package_name}", getAppReviews).Methods("GET")
+	router.HandleFunc("/hitec/crawl/developer/google-play/{developer_id}", getDeveloper).Methods("GET")
+	router.HandleFunc("/hitec/crawl/similar-graph/google-play/{package_name}", getSimilarGraph).Methods("GET")
+	router.HandleFunc("/hitec/crawl/search/google-play", getSearch).Methods("GET")
+	router.HandleFunc("/hitec/crawl/charts/google-play/{collection}/{category}", getChart).Methods("GET")
+	router.HandleFunc("/hitec/app-page/google-play/{package_name}/history", getAppPageHistoryHandler).Methods("GET")
+	router.HandleFunc("/hitec/app-page/google-play/{package_name}/diff", getAppPageDiffHandler).Methods("GET")
+	router.HandleFunc("/hitec/crawl/watchlist", getWatchlist).Methods("GET")
+	router.HandleFunc("/hitec/crawl/watchlist", postWatchlist).Methods("POST")
+	router.HandleFunc("/hitec/crawl/watchlist/{package_name}", deleteWatchlistEntry).Methods("DELETE")
+	router.HandleFunc("/hitec/crawl/selector-profile", getSelectorProfileHandler).Methods("GET")
+	router.HandleFunc("/hitec/crawl/selector-profile/reload", postSelectorProfileReload).Methods("POST")
+
+	// version 2 of the api, only the app pages changed their shape
+	routerV2 := router.PathPrefix("/v2").Subrouter()
+	routerV2.HandleFunc("/hitec/crawl/app-page/google-play/{package_name}", getAppPageV2).Methods("GET")
+	routerV2.HandleFunc("/hitec/crawl/app-page/google-play", postAppPagesV2).Methods("POST")
 	return router
 }
 
-func recoverAPICall(w http.ResponseWriter, page AppPage) {
+func recoverAPICall(w http.ResponseWriter) {
 	if r := recover(); r != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		page.Errors = append(page.Errors, requestError)
+		serveResponse(w, ErrorResponse{Status: http.StatusInternalServerError, Code: errorCodeInternal, Message: requestError}, http.StatusInternalServerError)
 	}
 }
 
 func getAppPage(w http.ResponseWriter, r *http.Request) {
-	appPage := AppPage{}
 	w.Header().Set("Content-Type", "application/json")
-	defer recoverAPICall(w, appPage)
+	defer recoverAPICall(w)
 
+	formatVersion, validFormatVersion := getFormatVersion(r)
+	if !validFormatVersion {
+		serveBadRequest(w, requestErrorFormat, mux.Vars(r)["package_name"])
+		return
+	}
+	appPage, crawled := crawlRequestedAppPage(w, r)
+	if crawled {
+		serveResponse(w, formatAppPage(w, appPage, formatVersion), http.StatusOK)
+	}
+}
+
+func getAppPageV2(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	defer recoverAPICall(w)
+
+	appPage, crawled := crawlRequestedAppPage(w, r)
+	if crawled {
+		serveResponse(w, appPage.v2(), http.StatusOK)
+	}
+}
+
+// crawls the app page of the request unless it is cached, errors are served directly and reported as not crawled
+func crawlRequestedAppPage(w http.ResponseWriter, r *http.Request) (AppPage, bool) {
 	// get request param
 	params := mux.Vars(r)
 	packageName := params["package_name"]
 
-	// crawl app reviews
-	appPage = Crawl(packageName)
-	serveResponse(w, appPage, http.StatusOK)
+	options, validOptions := getCrawlOptions(r)
+	if !validOptions {
+		serveBadRequest(w, requestErrorLocale, packageName)
+		return AppPage{}, false
+	}
+
+	// crawl app page unless it is cached, downloading the images always crawls it again
+	fresh, _ := strconv.ParseBool(r.URL.Query().Get("fresh"))
+	if downloadMedia, _ := strconv.ParseBool(r.URL.Query().Get("download_media")); downloadMedia {
+		if mediaDirectory == "" {
+			serveBadRequest(w, requestErrorMedia, packageName)
+			return AppPage{}, false
+		}
+		options.MediaDirectory = mediaDirectory
+		fresh = true
+	}
+	appPage, age, crawlError := CrawlCached(r.Context(), appPageCache, packageName, options, fresh)
+	if errors.Is(crawlError, ErrTimeout) && appPage.PackageName != "" {
+		// the app page itself was crawled in time, only sub-requests are missing
+		appPage.Errors = append(appPage.Errors, crawlError.Error())
+		return appPage, true
+	}
+	if crawlError != nil {
+		serveError(w, crawlError)
+		return appPage, false
+	}
+	setCacheHeaders(w, age)
+
+	return appPage, true
+}
+
+func postAppPages(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	defer recoverAPICall(w)
+
+	formatVersion, validFormatVersion := getFormatVersion(r)
+	if !validFormatVersion {
+		serveBadRequest(w, requestErrorFormat, "")
+		return
+	}
+	appPages, crawled := crawlRequestedAppPages(w, r)
+	if crawled {
+		serveResponse(w, formatAppPages(w, appPages, formatVersion), http.StatusOK)
+	}
+}
+
+func postAppPagesV2(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	defer recoverAPICall(w)
+
+	appPages, crawled := crawlRequestedAppPages(w, r)
+	if crawled {
+		appPagesV2 := make([]AppPageV2, len(appPages))
+		for position, appPage := range appPages {
+			appPagesV2[position] = appPage.v2()
+		}
+		serveResponse(w, appPagesV2, http.StatusOK)
+	}
+}
+
+// crawls the app pages of the package names in the request body, an invalid request is served directly and reported
+// as not crawled
+func crawlRequestedAppPages(w http.ResponseWriter, r *http.Request) ([]AppPage, bool) {
+	// get request body
+	var packageNames []string
+	decodeError := json.NewDecoder(r.Body).Decode(&packageNames)
+	if decodeError != nil || len(packageNames) == 0 || len(packageNames) > maxBatchSize {
+		serveBadRequest(w, requestErrorBatch+" (at most "+strconv.Itoa(maxBatchSize)+")", "")
+		return nil, false
+	}
+
+	options, validOptions := getCrawlOptions(r)
+	if !validOptions {
+		serveBadRequest(w, requestErrorLocale, "")
+		return nil, false
+	}
+
+	// crawl app pages
+	return CrawlBatch(r.Context(), packageNames, options, batchConcurrency), true
+}
+
+func getAppReviews(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	defer recoverAPICall(w)
+
+	packageName := mux.Vars(r)["package_name"]
+	options, validOptions := getCrawlOptions(r)
+	if !validOptions {
+		serveBadRequest(w, requestErrorLocale, packageName)
+		return
+	}
+	reviewOptions, validReviewOptions := getReviewOptions(r)
+	if !validReviewOptions {
+		serveBadRequest(w, requestErrorReview+strconv.Itoa(maxReviewLimit), packageName)
+		return
+	}
+
+	reviewPage, crawlError := CrawlReviews(r.Context(), packageName, reviewOptions, options)
+	if crawlError != nil {
+		serveError(w, crawlError)
+		return
+	}
+	serveResponse(w, reviewPage, http.StatusOK)
+}
+
+func getDeveloper(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	defer recoverAPICall(w)
+
+	developerID := mux.Vars(r)["developer_id"]
+	options, validOptions := getCrawlOptions(r)
+	if !validOptions {
+		serveBadRequest(w, requestErrorLocale, developerID)
+		return
+	}
+
+	developerPage, crawlError := CrawlDeveloper(r.Context(), developerID, options)
+	if crawlError != nil {
+		serveError(w, crawlError)
+		return
+	}
+	serveResponse(w, developerPage, http.StatusOK)
+}
+
+func getSimilarGraph(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	defer recoverAPICall(w)
+
+	packageName := mux.Vars(r)["package_name"]
+	options, validOptions := getCrawlOptions(r)
+	if !validOptions {
+		serveBadRequest(w, requestErrorLocale, packageName)
+		return
+	}
+	graphOptions, format, validGraphOptions := getSimilarGraphOptions(r)
+	if !validGraphOptions {
+		serveBadRequest(w, fmt.Sprintf(requestErrorGraph, maxSimilarGraphDepth, maxSimilarGraphMaxNodes), packageName)
+		return
+	}
+
+	// the crawl timeout applies to every app page of the graph, the graph timeout to the whole graph
+	graph, crawlError := CrawlSimilarGraph(r.Context(), appPageCache, packageName, graphOptions, options, batchConcurrency)
+	if crawlError != nil {
+		serveError(w, crawlError)
+		return
+	}
+	switch format {
+	case SimilarGraphFormatGraphML:
+		w.Header().Set("Content-Type", "application/graphml+xml")
+		w.Write([]byte(graph.GraphML()))
+	case SimilarGraphFormatDOT:
+		w.Header().Set("Content-Type", "text/vnd.graphviz")
+		w.Write([]byte(graph.DOT()))
+	default:
+		serveResponse(w, graph, http.StatusOK)
+	}
+}
+
+func getSearch(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	defer recoverAPICall(w)
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		serveBadRequest(w, requestErrorSearch, "")
+		return
+	}
+	options, validOptions := getCrawlOptions(r)
+	if !validOptions {
+		serveBadRequest(w, requestErrorLocale, query)
+		return
+	}
+
+	appList, crawlError := CrawlSearch(r.Context(), query, options)
+	if crawlError != nil {
+		serveError(w, crawlError)
+		return
+	}
+	serveResponse(w, appList, http.StatusOK)
+}
+
+func getChart(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	defer recoverAPICall(w)
+
+	params := mux.Vars(r)
+	collection := params["collection"]
+	category := params["category"]
+	if !isValidChart(collection, category) {
+		serveBadRequest(w, requestErrorChart, "")
+		return
+	}
+	options, validOptions := getCrawlOptions(r)
+	if !validOptions {
+		serveBadRequest(w, requestErrorLocale, "")
+		return
+	}
+
+	appList, crawlError := CrawlChart(r.Context(), collection, category, options)
+	if crawlError != nil {
+		serveError(w, crawlError)
+		return
+	}
+	serveResponse(w, appList, http.StatusOK)
+}
+
+func getAppPageHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	defer recoverAPICall(w)
+
+	packageName := mux.Vars(r)["package_name"]
+	options, validOptions := getCrawlOptions(r)
+	if !validOptions {
+		serveBadRequest(w, requestErrorLocale, packageName)
+		return
+	}
+
+	history, historyError := getAppPageHistory(appPageStore, packageName, options)
+	if historyError != nil {
+		serveError(w, historyError)
+		return
+	}
+	serveResponse(w, history, http.StatusOK)
+}
+
+func getAppPageDiffHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	defer recoverAPICall(w)
+
+	packageName := mux.Vars(r)["package_name"]
+	options, validOptions := getCrawlOptions(r)
+	if !validOptions {
+		serveBadRequest(w, requestErrorLocale, packageName)
+		return
+	}
+	from, validFrom := getDateParameter(r, "from")
+	to, validTo := getDateParameter(r, "to")
+	if !validFrom || !validTo {
+		serveBadRequest(w, requestErrorDiff, packageName)
+		return
+	}
+
+	diff, diffError := getAppPageDiff(appPageStore, packageName, options, from, to)
+	if diffError != nil {
+		serveError(w, diffError)
+		return
+	}
+	serveResponse(w, diff, http.StatusOK)
+}
+
+func getWatchlist(w http.ResponseWriter, r *http.Request) {
+	serveResponse(w, watchlistScheduler.Watchlist(), http.StatusOK)
+}
+
+func postWatchlist(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	defer recoverAPICall(w)
+
+	var packageNames []string
+	decodeError := json.NewDecoder(r.Body).Decode(&packageNames)
+	if decodeError != nil || len(packageNames) == 0 {
+		serveBadRequest(w, requestErrorBatch, "")
+		return
+	}
+
+	addError := watchlistScheduler.Add(packageNames)
+	if addError != nil {
+		serveResponse(w, ErrorResponse{Status: http.StatusInternalServerError, Code: errorCodeInternal, Message: addError.Error()}, http.StatusInternalServerError)
+		return
+	}
+	serveResponse(w, watchlistScheduler.Watchlist(), http.StatusOK)
+}
+
+func deleteWatchlistEntry(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	defer recoverAPICall(w)
+
+	packageName := mux.Vars(r)["package_name"]
+	removed, removeError := watchlistScheduler.Remove(packageName)
+	if removeError != nil {
+		serveResponse(w, ErrorResponse{Status: http.StatusInternalServerError, Code: errorCodeInternal, Message: removeError.Error(), PackageName: packageName}, http.StatusInternalServerError)
+		return
+	}
+	if !removed {
+		serveResponse(w, ErrorResponse{Status: http.StatusNotFound, Code: errorCodeNotFound, Message: requestErrorWatch, PackageName: packageName}, http.StatusNotFound)
+		return
+	}
+	serveResponse(w, watchlistScheduler.Watchlist(), http.StatusOK)
+}
+
+func getSelectorProfileHandler(w http.ResponseWriter, r *http.Request) {
+	serveResponse(w, getSelectorProfile(), http.StatusOK)
+}
+
+func postSelectorProfileReload(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	defer recoverAPICall(w)
+
+	profile, reloadError := reloadSelectorProfile(selectorProfilePath)
+	if reloadError != nil {
+		serveResponse(w, ErrorResponse{Status: http.StatusInternalServerError, Code: errorCodeInternal, Message: reloadError.Error()}, http.StatusInternalServerError)
+		return
+	}
+	serveResponse(w, profile, http.StatusOK)
+}
+
+// returns the crawl options of the request and whether the parameters are valid
+func getCrawlOptions(r *http.Request) (CrawlOptions, bool) {
+	options := CrawlOptions{
+		Fetcher:  pageFetcher,
+		Language: r.URL.Query().Get("hl"),
+		Country:  r.URL.Query().Get("gl"),
+		Store:    appPageStore,
+		Timeout:  crawlTimeout,
+	}
+
+	return options, isValidLocaleIdentifier(options.Language) && isValidLocaleIdentifier(options.Country)
+}
+
+// returns the version of the response format the request asks for and whether it is a known version, the deprecated
+// format stays the default until all clients opted in to the current one
+func getFormatVersion(r *http.Request) (int, bool) {
+	formatVersion := r.URL.Query().Get("format_version")
+	if formatVersion == "" {
+		return FormatVersionLegacy, true
+	}
+	formatVersionNumber, parseError := strconv.Atoi(formatVersion)
+	if parseError != nil || formatVersionNumber < FormatVersionLegacy || formatVersionNumber > FormatVersionCurrent {
+		return FormatVersionLegacy, false
+	}
+
+	return formatVersionNumber, true
+}
+
+// returns the app page in the response format of the version, the deprecated format is marked in the headers
+func formatAppPage(writer http.ResponseWriter, appPage AppPage, formatVersion int) interface{} {
+	if formatVersion == FormatVersionLegacy {
+		writer.Header().Set("Deprecation", "true")
+		return appPage.legacy()
+	}
+
+	return appPage
+}
+
+// returns the app pages in the response format of the version, see formatAppPage
+func formatAppPages(writer http.ResponseWriter, appPages []AppPage, formatVersion int) interface{} {
+	if formatVersion != FormatVersionLegacy {
+		return appPages
+	}
+	writer.Header().Set("Deprecation", "true")
+	legacyAppPages := make([]LegacyAppPage, len(appPages))
+	for position, appPage := range appPages {
+		legacyAppPages[position] = appPage.legacy()
+	}
+
+	return legacyAppPages
+}
+
+// returns the review options of the request and whether the parameters are valid
+func getReviewOptions(r *http.Request) (ReviewOptions, bool) {
+	query := r.URL.Query()
+	reviewOptions := ReviewOptions{Sort: query.Get("sort"), ContinuationToken: query.Get("continuation_token")}
+	if _, knownSort := reviewSortIDs[reviewOptions.Sort]; reviewOptions.Sort != "" && !knownSort {
+		return reviewOptions, false
+	}
+	if rating := query.Get("rating"); rating != "" {
+		ratingNumber, parseError := strconv.Atoi(rating)
+		if parseError != nil || ratingNumber < 1 || ratingNumber > 5 {
+			return reviewOptions, false
+		}
+		reviewOptions.Rating = ratingNumber
+	}
+	if limit := query.Get("limit"); limit != "" {
+		limitNumber, parseError := strconv.Atoi(limit)
+		if parseError != nil || limitNumber < 1 || limitNumber > maxReviewLimit {
+			return reviewOptions, false
+		}
+		reviewOptions.Limit = limitNumber
+	}
+
+	return reviewOptions, true
+}
+
+// returns the options of the similar graph, the export format of the request and whether the parameters are valid
+func getSimilarGraphOptions(r *http.Request) (SimilarGraphOptions, string, bool) {
+	query := r.URL.Query()
+	graphOptions := SimilarGraphOptions{Timeout: similarGraphTimeout}
+	format := query.Get("format")
+	if format == "" {
+		format = SimilarGraphFormatJSON
+	}
+	if format != SimilarGraphFormatJSON && format != SimilarGraphFormatGraphML && format != SimilarGraphFormatDOT {
+		return graphOptions, format, false
+	}
+	if depth := query.Get("depth"); depth != "" {
+		depthNumber, parseError := strconv.Atoi(depth)
+		if parseError != nil || depthNumber < 1 || depthNumber > maxSimilarGraphDepth {
+			return graphOptions, format, false
+		}
+		graphOptions.Depth = depthNumber
+	}
+	if maxNodes := query.Get("max_nodes"); maxNodes != "" {
+		maxNodesNumber, parseError := strconv.Atoi(maxNodes)
+		if parseError != nil || maxNodesNumber < 1 || maxNodesNumber > maxSimilarGraphMaxNodes {
+			return graphOptions, format, false
+		}
+		graphOptions.MaxNodes = maxNodesNumber
+	}
+
+	return graphOptions, format, true
+}
+
+// returns the date of the query parameter formatted like 20181231, zero if it is not set, and whether it is valid
+func getDateParameter(r *http.Request, name string) (int64, bool) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return 0, true
+	}
+	_, parseError := time.Parse("20060102", value)
+	if parseError != nil {
+		return 0, false
+	}
+	date, _ := strconv.ParseInt(value, 10, 64)
+
+	return date, true
 }
 
 // serves the generated content
-func serveResponse(writer http.ResponseWriter, page AppPage, status int) {
+func serveResponse(writer http.ResponseWriter, content interface{}, status int) {
 	writer.Header().Set("Content-Type", "application/json")
 	writer.WriteHeader(status)
 	encoder := json.NewEncoder(writer)
 	encoder.SetEscapeHTML(false)
-	encoder.Encode(page)
+	encoder.Encode(content)
+}
+
+// serves the json error response matching the error of the crawler
+func serveError(writer http.ResponseWriter, err error) {
+	errorResponse := getErrorResponse(err)
+	var crawlError *CrawlError
+	if errors.As(err, &crawlError) && crawlError.RetryAfter != "" {
+		writer.Header().Set("Retry-After", crawlError.RetryAfter)
+	}
+	serveResponse(writer, errorResponse, errorResponse.Status)
+}
+
+// tells the client how old the app page is and how long it stays in the cache
+func setCacheHeaders(writer http.ResponseWriter, age time.Duration) {
+	if appPageCache == nil {
+		writer.Header().Set("Cache-Control", "no-store")
+		return
+	}
+	maxAge := int(appPageCache.TTL().Seconds())
+	writer.Header().Set("Cache-Control", "max-age="+strconv.Itoa(maxAge))
+	writer.Header().Set("Age", strconv.Itoa(int(age.Seconds())))
+}
+
+// serves the json error response for invalid requests
+func serveBadRequest(writer http.ResponseWriter, message string, packageName string) {
+	serveResponse(writer, ErrorResponse{Status: http.StatusBadRequest, Code: errorCodeBadRequest, Message: message, PackageName: packageName}, http.StatusBadRequest)
+}
+
+// checks if the language or country parameter is empty or a valid code
+func isValidLocaleIdentifier(identifier string) bool {
+	return identifier == "" || localeIdentifierPattern.MatchString(identifier)
+}
+
+// returns the value of the environment variable as number or the fallback if it isn't set or invalid
+func getEnvInt(name string, fallback int) int {
+	value, parseError := strconv.Atoi(os.Getenv(name))
+	if parseError != nil || value < 1 {
+		return fallback
+	}
+
+	return value
+}
+
+// returns the value of the environment variable as number like getEnvInt, but also accepts zero to turn off something
+// like the retries
+func getEnvNonNegativeInt(name string, fallback int) int {
+	value, parseError := strconv.Atoi(os.Getenv(name))
+	if parseError != nil || value < 0 {
+		return fallback
+	}
+
+	return value
 }
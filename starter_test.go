@@ -7,12 +7,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 )
 
 var router *mux.Router
+var routerFetcher *testFetcher
 
 func TestMain(m *testing.M) {
 	fmt.Println("--- Start Tests")
@@ -29,11 +32,14 @@ func TestMain(m *testing.M) {
 
 func setup() {
 	fmt.Println("--- --- setup")
+	routerFetcher = newTestFetcher(loadAppPageFixtures())
+	pageFetcher = routerFetcher
 	router = makeRouter()
 }
 
 func tearDown() {
 	fmt.Println("--- --- tear down")
+	routerFetcher.Close()
 }
 
 func buildRequest(method, endpoint string, payload io.Reader, t *testing.T) *http.Request {
@@ -62,7 +68,6 @@ func TestGetAppPage(t *testing.T) {
 	for _, appId := range []string{
 		"com.whatsapp",             // free
 		"com.ustwo.monumentvalley", // paid, contains in-app-purchases
-		"com.does.not.exists.122",  // does not exist
 	} {
 		endpointCheckOne := fmt.Sprintf(endpoint, appId)
 		req := buildRequest(method, endpointCheckOne, nil, t)
@@ -73,10 +78,206 @@ func TestGetAppPage(t *testing.T) {
 			t.Errorf("Status code differs. Expected %d .\n Got %d instead", http.StatusOK, status)
 		}
 
-		var appPages AppPage
+		var appPages LegacyAppPage
 		err := json.NewDecoder(rr.Body).Decode(&appPages)
 		if err != nil {
 			t.Errorf("Did not receive a proper formed json")
 		}
 	}
+
+	/*
+	 * test for not found CHECK 2
+	 */
+	req := buildRequest(method, fmt.Sprintf(endpoint, "com.does.not.exists.122"), nil, t)
+	rr := executeRequest(req)
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("Status code differs. Expected %d .\n Got %d instead", http.StatusNotFound, status)
+	}
+	var errorResponse ErrorResponse
+	if err := json.NewDecoder(rr.Body).Decode(&errorResponse); err != nil {
+		t.Errorf("Did not receive a proper formed json")
+	}
+	if errorResponse.Code != errorCodeNotFound || errorResponse.PackageName != "com.does.not.exists.122" {
+		t.Errorf("error response should contain code %q and the package name, got %+v", errorCodeNotFound, errorResponse)
+	}
+}
+
+func TestGetAppPageLocale(t *testing.T) {
+	fmt.Println("start TestGetAppPageLocale")
+	var method = "GET"
+	var endpoint = "/hitec/crawl/app-page/google-play/com.whatsapp?hl=%s&gl=%s"
+
+	req := buildRequest(method, fmt.Sprintf(endpoint, "de", "AT"), nil, t)
+	rr := executeRequest(req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Status code differs. Expected %d .\n Got %d instead", http.StatusOK, status)
+	}
+	var appPage LegacyAppPage
+	if err := json.NewDecoder(rr.Body).Decode(&appPage); err != nil {
+		t.Errorf("Did not receive a proper formed json")
+	}
+	if appPage.Language != "de" || appPage.Country != "AT" {
+		t.Errorf("language and country should be \"de\" and \"AT\", got %q and %q", appPage.Language, appPage.Country)
+	}
+	requestedAppPage := false
+	for _, request := range routerFetcher.Requested() {
+		requestedAppPage = requestedAppPage || request == "/store/apps/details?id=com.whatsapp&hl=de&gl=AT"
+	}
+	if !requestedAppPage {
+		t.Errorf("app page should be requested in language \"de\" and country \"AT\"")
+	}
+
+	req = buildRequest(method, fmt.Sprintf(endpoint, "de", "<script>"), nil, t)
+	rr = executeRequest(req)
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("Status code differs. Expected %d .\n Got %d instead", http.StatusBadRequest, status)
+	}
+}
+
+func TestGetAppPageFormatVersion(t *testing.T) {
+	fmt.Println("start TestGetAppPageFormatVersion")
+	var endpoint = "/hitec/crawl/app-page/google-play/com.whatsapp?fresh=true&format_version=%s"
+
+	rr := executeRequest(buildRequest("GET", fmt.Sprintf(endpoint, "2"), nil, t))
+	var appPage map[string]interface{}
+	json.NewDecoder(rr.Body).Decode(&appPage)
+	dateCrawled, _ := appPage["date_crawled"].(string)
+	if _, parseError := time.Parse(time.RFC3339, dateCrawled); parseError != nil || !strings.HasSuffix(dateCrawled, "Z") || strings.Contains(dateCrawled, ".") {
+		t.Errorf("date_crawled should be a timestamp in UTC accurate to the second, got %v", appPage["date_crawled"])
+	}
+	if appPage["last_update"] != "2017-10-27T00:00:00Z" || rr.Header().Get("Deprecation") != "" {
+		t.Errorf("last_update should be a timestamp, got %v", appPage["last_update"])
+	}
+
+	// the deprecated format stays the default
+	for _, formatVersion := range []string{"", "1"} {
+		rr = executeRequest(buildRequest("GET", fmt.Sprintf(endpoint, formatVersion), nil, t))
+		var legacyAppPage map[string]interface{}
+		json.NewDecoder(rr.Body).Decode(&legacyAppPage)
+		if dateCrawled, isNumber := legacyAppPage["date_crawled"].(float64); !isNumber || dateCrawled < 20190101 {
+			t.Errorf("date_crawled of format version 1 should be a day like 20181231, got %v", legacyAppPage["date_crawled"])
+		}
+		if legacyAppPage["last_update"] != float64(20171027) || legacyAppPage["release_date"] != float64(0) {
+			t.Errorf("dates of format version 1 should be days like 20181231, got %v and %v", legacyAppPage["last_update"], legacyAppPage["release_date"])
+		}
+		if rr.Header().Get("Deprecation") != "true" {
+			t.Errorf("format version 1 should be marked as deprecated")
+		}
+	}
+
+	rr = executeRequest(buildRequest("GET", fmt.Sprintf(endpoint, "3"), nil, t))
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("unknown format version should be rejected, got status %d", status)
+	}
+}
+
+func TestGetAppPageDownloadMedia(t *testing.T) {
+	fmt.Println("start TestGetAppPageDownloadMedia")
+	mediaDirectory = ""
+
+	req := buildRequest("GET", "/hitec/crawl/app-page/google-play/com.whatsapp?download_media=true", nil, t)
+	rr := executeRequest(req)
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("downloading media without a media directory should be rejected, got status %d", status)
+	}
+}
+
+func TestPostAppPages(t *testing.T) {
+	fmt.Println("start TestPostAppPages")
+	var method = "POST"
+	var endpoint = "/hitec/crawl/app-page/google-play"
+
+	req := buildRequest(method, endpoint, strings.NewReader(`["com.whatsapp", "com.does.not.exists.122"]`), t)
+	rr := executeRequest(req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Status code differs. Expected %d .\n Got %d instead", http.StatusOK, status)
+	}
+	var appPages []LegacyAppPage
+	if err := json.NewDecoder(rr.Body).Decode(&appPages); err != nil {
+		t.Errorf("Did not receive a proper formed json")
+	}
+	if len(appPages) != 2 || appPages[0].PackageName != "com.whatsapp" || appPages[1].PackageName != "com.does.not.exists.122" {
+		t.Errorf("there should be one app page per package name, got %v", appPages)
+	}
+	if appPages[0].DateCrawled < 20190101 || rr.Header().Get("Deprecation") != "true" {
+		t.Errorf("app pages should be returned in the deprecated format by default, got date_crawled %d", appPages[0].DateCrawled)
+	}
+
+	for _, payload := range []string{`{"package_name": "com.whatsapp"}`, `[]`, `not json`} {
+		req = buildRequest(method, endpoint, strings.NewReader(payload), t)
+		rr = executeRequest(req)
+		if status := rr.Code; status != http.StatusBadRequest {
+			t.Errorf("Status code differs for %s. Expected %d .\n Got %d instead", payload, http.StatusBadRequest, status)
+		}
+	}
+}
+
+func TestGetAppPageV2(t *testing.T) {
+	fmt.Println("start TestGetAppPageV2")
+	var endpoint = "%s/hitec/crawl/app-page/google-play/com.ustwo.monumentvalley"
+
+	rr := executeRequest(buildRequest("GET", fmt.Sprintf(endpoint, "/v2"), nil, t))
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Status code differs. Expected %d .\n Got %d instead", http.StatusOK, status)
+	}
+	var appPage AppPageV2
+	if err := json.NewDecoder(rr.Body).Decode(&appPage); err != nil {
+		t.Fatalf("Did not receive a proper formed json")
+	}
+	if appPage.Pricing.Free || appPage.Pricing.Price == 0 || !appPage.Pricing.InAppPurchases {
+		t.Errorf("pricing should be paid with in-app purchases, got %+v", appPage.Pricing)
+	}
+	if appPage.Developer.Name != "ustwo games" || appPage.Developer.Website != "http://www.monumentvalleygame.com" {
+		t.Errorf("developer should have its name and website, got %+v", appPage.Developer)
+	}
+	if appPage.FieldSources["developer.name"] == "" || appPage.FieldSources[fieldDeveloperName] != "" {
+		t.Errorf("field sources should only contain fields of version 2, got %v", appPage.FieldSources)
+	}
+	if appPage.Ratings.Average == 0 || appPage.Ratings.Count == 0 || appPage.Installs.Minimum == 0 {
+		t.Errorf("ratings, developer and installs should be nested, got %+v, %+v and %+v", appPage.Ratings, appPage.Developer, appPage.Installs)
+	}
+	if appPage.FieldSources["pricing.price"] == "" || appPage.FieldSources[fieldPriceValue] != "" {
+		t.Errorf("field sources should be keyed by the paths of version 2, got %v", appPage.FieldSources)
+	}
+
+	// the route of version 1 keeps its shape
+	rr = executeRequest(buildRequest("GET", fmt.Sprintf(endpoint, ""), nil, t))
+	var legacyAppPage map[string]interface{}
+	json.NewDecoder(rr.Body).Decode(&legacyAppPage)
+	if _, found := legacyAppPage["in_app_purchase"]; !found || legacyAppPage["pricing"] != nil {
+		t.Errorf("app page of version 1 should not change its shape, got %v", legacyAppPage)
+	}
+}
+
+func TestPostAppPagesV2(t *testing.T) {
+	fmt.Println("start TestPostAppPagesV2")
+	var endpoint = "/v2/hitec/crawl/app-page/google-play"
+
+	rr := executeRequest(buildRequest("POST", endpoint, strings.NewReader(`["com.whatsapp"]`), t))
+	var appPages []AppPageV2
+	if err := json.NewDecoder(rr.Body).Decode(&appPages); err != nil {
+		t.Fatalf("Did not receive a proper formed json")
+	}
+	if len(appPages) != 1 || !appPages[0].Pricing.Free || appPages[0].Ratings.Count == 0 {
+		t.Errorf("there should be one app page of version 2 per package name, got %+v", appPages)
+	}
+
+	rr = executeRequest(buildRequest("POST", endpoint, strings.NewReader(`[]`), t))
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("Status code differs. Expected %d .\n Got %d instead", http.StatusBadRequest, status)
+	}
+}
+
+func TestGetEnvNonNegativeInt(t *testing.T) {
+	defer os.Unsetenv("TEST_RETRIES")
+	for value, want := range map[string]int{"0": 0, "3": 3, "-1": 2, "many": 2, "": 2} {
+		os.Setenv("TEST_RETRIES", value)
+		if got := getEnvNonNegativeInt("TEST_RETRIES", 2); got != want {
+			t.Errorf("%q should be read as %d, got %d", value, want, got)
+		}
+	}
+	os.Setenv("TEST_RETRIES", "0")
+	if got := getEnvInt("TEST_RETRIES", 2); got != 2 {
+		t.Errorf("zero should fall back to the default for positive numbers, got %d", got)
+	}
 }
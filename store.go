@@ -0,0 +1,151 @@
+package main
+
+import (
+	"sort"
+	"sync"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	defaultMongoDatabase   = "google_play"
+	mongoCollectionAppPage = "app_page"
+)
+
+// Store persists crawled app pages, there is one app page per package, storefront and day in UTC so that the stored
+// app pages build up a time series with a granularity of one day. Saving an app page replaces the one crawled earlier
+// on the same day
+type Store interface {
+	SaveAppPage(appPage AppPage) error
+	// returns the app pages of the package in the storefront ordered by the date they were crawled
+	AppPages(packageName string, language string, country string) ([]AppPage, error)
+	Close()
+}
+
+// key of an app page in a store
+type appPageStoreKey struct {
+	PackageName string
+	DayCrawled  int64
+	Language    string
+	Country     string
+}
+
+// returns the key the app page is stored under
+func getAppPageStoreKey(appPage AppPage) appPageStoreKey {
+	return appPageStoreKey{PackageName: appPage.PackageName, DayCrawled: getDayNumber(appPage.DateCrawled), Language: appPage.Language, Country: appPage.Country}
+}
+
+// MemoryStore keeps the app pages in memory, it is meant for tests
+type MemoryStore struct {
+	mutex    sync.Mutex
+	appPages map[appPageStoreKey]AppPage
+}
+
+// NewMemoryStore returns an empty store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{appPages: map[appPageStoreKey]AppPage{}}
+}
+
+// SaveAppPage replaces the app page crawled on the same day
+func (store *MemoryStore) SaveAppPage(appPage AppPage) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.appPages[getAppPageStoreKey(appPage)] = appPage
+
+	return nil
+}
+
+// AppPages returns the stored app pages of the package in the storefront ordered by the date they were crawled
+func (store *MemoryStore) AppPages(packageName string, language string, country string) ([]AppPage, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	var appPages []AppPage
+	for key, appPage := range store.appPages {
+		if key.PackageName == packageName && key.Language == language && key.Country == country {
+			appPages = append(appPages, appPage)
+		}
+	}
+	sort.Slice(appPages, func(i, j int) bool {
+		return appPages[i].DateCrawled.Before(appPages[j].DateCrawled)
+	})
+
+	return appPages, nil
+}
+
+// Close does nothing, the app pages stay available
+func (store *MemoryStore) Close() {}
+
+// MongoStore keeps the app pages in the "app_page" collection of a MongoDB database
+type MongoStore struct {
+	session  *mgo.Session
+	database string
+}
+
+// document of an app page in MongoDB, the day it was crawled is stored next to the time of the crawl so that the
+// unique index allows only one app page per package, storefront and day
+type mongoAppPage struct {
+	AppPage    `bson:",inline"`
+	DayCrawled int64 `bson:"day_crawled"`
+}
+
+// NewMongoStore connects to the MongoDB at the url and makes sure the collection is indexed by the key of the app pages
+func NewMongoStore(url string, database string) (*MongoStore, error) {
+	session, dialError := mgo.Dial(url)
+	if dialError != nil {
+		return nil, dialError
+	}
+	session.SetMode(mgo.Monotonic, true)
+
+	store := &MongoStore{session: session, database: database}
+	index := mgo.Index{Key: []string{"package_name", "day_crawled", "language", "country"}, Unique: true}
+	indexError := session.DB(database).C(mongoCollectionAppPage).EnsureIndex(index)
+	if indexError != nil {
+		session.Close()
+		return nil, indexError
+	}
+
+	return store, nil
+}
+
+// SaveAppPage inserts the app page or replaces the app page crawled on the same day
+func (store *MongoStore) SaveAppPage(appPage AppPage) error {
+	session := store.session.Copy()
+	defer session.Close()
+
+	// the app page replaces the one crawled at any time of the same day in UTC
+	key := getAppPageStoreKey(appPage)
+	selector := bson.M{
+		"package_name": key.PackageName,
+		"day_crawled":  key.DayCrawled,
+		"language":     key.Language,
+		"country":      key.Country,
+	}
+	collection := session.DB(store.database).C(mongoCollectionAppPage)
+	_, upsertError := collection.Upsert(selector, mongoAppPage{AppPage: appPage, DayCrawled: key.DayCrawled})
+	if mgo.IsDup(upsertError) {
+		// another upsert of the same day inserted the document first, so this one only has to replace it
+		_, upsertError = collection.Upsert(selector, mongoAppPage{AppPage: appPage, DayCrawled: key.DayCrawled})
+	}
+
+	return upsertError
+}
+
+// AppPages returns the stored app pages of the package in the storefront ordered by the date they were crawled
+func (store *MongoStore) AppPages(packageName string, language string, country string) ([]AppPage, error) {
+	session := store.session.Copy()
+	defer session.Close()
+
+	var appPages []AppPage
+	query := bson.M{"package_name": packageName, "language": language, "country": country}
+	findError := session.DB(store.database).C(mongoCollectionAppPage).Find(query).Sort("date_crawled").All(&appPages)
+
+	return appPages, findError
+}
+
+// Close closes the connection to the database
+func (store *MongoStore) Close() {
+	store.session.Close()
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	firstDay := time.Date(2019, 1, 1, 23, 59, 59, 0, time.UTC)
+	secondDay := time.Date(2019, 1, 2, 8, 0, 0, 0, time.UTC)
+	store.SaveAppPage(AppPage{PackageName: "com.whatsapp", DateCrawled: secondDay, Language: "en", Rating: 4.3})
+	store.SaveAppPage(AppPage{PackageName: "com.whatsapp", DateCrawled: firstDay, Language: "en", Rating: 4.2})
+	// the second crawl on the same day replaces the first one
+	store.SaveAppPage(AppPage{PackageName: "com.whatsapp", DateCrawled: secondDay.Add(12 * time.Hour), Language: "en", Rating: 4.4})
+	store.SaveAppPage(AppPage{PackageName: "com.whatsapp", DateCrawled: secondDay, Language: "de", Rating: 4.4})
+	store.SaveAppPage(AppPage{PackageName: "com.ustwo.monumentvalley", DateCrawled: secondDay, Language: "en"})
+
+	appPages, _ := store.AppPages("com.whatsapp", "en", "")
+	if len(appPages) != 2 {
+		t.Fatalf("store should contain 2 english app pages of com.whatsapp, got %d", len(appPages))
+	}
+	if !appPages[0].DateCrawled.Equal(firstDay) || appPages[0].Rating != 4.2 {
+		t.Errorf("app pages should be ordered by date crawled, got %+v", appPages[0])
+	}
+	for _, appPage := range appPages[1:] {
+		if appPage.Rating != 4.4 {
+			t.Errorf("app page of the same day should be replaced, got rating %v", appPage.Rating)
+		}
+	}
+}
+
+func TestCrawlStoresAppPage(t *testing.T) {
+	fetcher := newTestFetcher(loadAppPageFixtures())
+	defer fetcher.Close()
+	store := NewMemoryStore()
+
+	if _, err := Crawl("com.whatsapp", CrawlOptions{Fetcher: fetcher, Store: store}); err != nil {
+		t.Fatalf("crawl should succeed : %v", err)
+	}
+	Crawl("com.does.not.exists.122", CrawlOptions{Fetcher: fetcher, Store: store})
+
+	if appPages, _ := store.AppPages("com.whatsapp", "en", ""); len(appPages) != 1 || appPages[0].Name != "WhatsApp Messenger" {
+		t.Errorf("crawled app page should be stored, got %+v", appPages)
+	}
+	if appPages, _ := store.AppPages("com.does.not.exists.122", "en", ""); len(appPages) != 0 {
+		t.Errorf("app page which could not be crawled should not be stored")
+	}
+}
+
+func TestMongoAppPageDocument(t *testing.T) {
+	dateCrawled := time.Date(2019, 1, 2, 8, 0, 0, 0, time.UTC)
+	document, err := bson.Marshal(mongoAppPage{AppPage: AppPage{PackageName: "com.whatsapp", DateCrawled: dateCrawled}, DayCrawled: 20190102})
+	if err != nil {
+		t.Fatalf("document should be encoded : %v", err)
+	}
+	var fields bson.M
+	bson.Unmarshal(document, &fields)
+	if fields["day_crawled"] != int64(20190102) || fields["package_name"] != "com.whatsapp" {
+		t.Errorf("day and fields of the app page should be on the top level of the document, got %v", fields)
+	}
+	var appPage AppPage
+	if err := bson.Unmarshal(document, &appPage); err != nil || !appPage.DateCrawled.Equal(dateCrawled) {
+		t.Errorf("document should be read back as app page, got %+v and %v", appPage, err)
+	}
+}
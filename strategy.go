@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/OlegSchmidt/soup"
+)
+
+const (
+	// names of the extraction strategies as recorded in the field sources of an app page
+	strategyNameDOM      = "dom"
+	strategyNameJSONLD   = "json-ld"
+	strategyNameInitData = "init-data"
+
+	// fields of the app page which are extracted by the strategies, named like their json keys
+	fieldName                     = "name"
+	fieldCategory                 = "category"
+	fieldUsk                      = "usk"
+	fieldPrice                    = "price"
+	fieldPriceValue               = "price_value"
+	fieldPriceCurrency            = "price_currency"
+	fieldDescription              = "description"
+	fieldWhatsNew                 = "whats_new"
+	fieldRating                   = "rating"
+	fieldStarsCount               = "stars_count"
+	fieldCountPerRating           = "count_per_rating"
+	fieldPercentPerRating         = "percent_per_rating"
+	fieldEstimatedDownloadNumber  = "estimated_download_number"
+	fieldRealInstalls             = "real_installs"
+	fieldInstallBucket            = "install_bucket"
+	fieldDeveloperName            = "developer"
+	fieldDeveloperID              = "developer_id"
+	fieldTopDeveloper             = "top_developer"
+	fieldContainsAds              = "contains_ads"
+	fieldInAppPurchases           = "in_app_purchase"
+	fieldLastUpdate               = "last_update"
+	fieldReleaseDate              = "release_date"
+	fieldRequiresOsVersion        = "requires_os_version"
+	fieldCurrentSoftwareVersion   = "current_software_version"
+	fieldSize                     = "size"
+	fieldContentRatingDescriptors = "content_rating_descriptors"
+	fieldInteractiveElements      = "interactive_elements"
+	fieldInAppProducts            = "in_app_products"
+	fieldOfferedBy                = "offered_by"
+	fieldDeveloperWebsite         = "developer_website"
+	fieldDeveloperEmail           = "developer_email"
+	fieldDeveloperAddress         = "developer_address"
+	fieldPrivacyPolicyURL         = "privacy_policy_url"
+	fieldDataSafety               = "data_safety"
+	fieldPermissions              = "permissions"
+	fieldIconURL                  = "icon_url"
+	fieldScreenshots              = "screenshots"
+	fieldFeatureGraphicURL        = "feature_graphic_url"
+	fieldPromoVideoID             = "promo_video_id"
+	fieldSimilarApps              = "similar_apps"
+)
+
+// ExtractionStrategy extracts the fields of an app page from the parsed html in one specific way
+type ExtractionStrategy interface {
+	Name() string
+	Extract(ctx context.Context, document soup.Root, options CrawlOptions) Extraction
+}
+
+// strategies used if the options don't name any, a field is taken from the first strategy which could extract it
+var defaultStrategies = []ExtractionStrategy{domStrategy{}, jsonLDStrategy{}, initDataStrategy{}}
+
+// Extraction model, result of a single strategy
+type Extraction struct {
+	Strategy string
+	AppPage  AppPage
+	// fields of the app page the strategy could extract
+	Filled map[string]bool
+	// fields the strategy failed to extract, together with the reason
+	Errors map[string]string
+	// errors not related to a single field
+	PageErrors []string
+}
+
+// returns an empty extraction of the strategy
+func newExtraction(strategy string) Extraction {
+	return Extraction{Strategy: strategy, Filled: map[string]bool{}, Errors: map[string]string{}}
+}
+
+// marks the fields as filled if there was no error, otherwise the error is recorded for the fields
+func (extraction *Extraction) track(err error, fields ...string) {
+	for _, field := range fields {
+		if err == nil {
+			extraction.Filled[field] = true
+		} else {
+			extraction.Errors[field] = err.Error()
+		}
+	}
+}
+
+// marks the fields as filled
+func (extraction *Extraction) fill(fields ...string) {
+	extraction.track(nil, fields...)
+}
+
+// copies every extracted field into the app page, taken from the first extraction which filled it, errors are only
+// kept for fields which no strategy could fill
+func mergeExtractions(appPage AppPage, extractions []Extraction) AppPage {
+	appPage.FieldSources = map[string]string{}
+	for _, extraction := range extractions {
+		appPage.Errors = append(appPage.Errors, extraction.PageErrors...)
+	}
+
+	appPageValue := reflect.ValueOf(&appPage).Elem()
+	appPageType := appPageValue.Type()
+	for position := 0; position < appPageType.NumField(); position++ {
+		field := strings.Split(appPageType.Field(position).Tag.Get("json"), ",")[0]
+		filled := false
+		var fieldErrors []string
+		for _, extraction := range extractions {
+			if extraction.Filled[field] {
+				appPageValue.Field(position).Set(reflect.ValueOf(extraction.AppPage).Field(position))
+				appPage.FieldSources[field] = extraction.Strategy
+				filled = true
+				break
+			}
+			if fieldError, exists := extraction.Errors[field]; exists {
+				fieldErrors = append(fieldErrors, fieldError)
+			}
+		}
+		if !filled {
+			for _, fieldError := range fieldErrors {
+				if !containsString(appPage.Errors, fieldError) {
+					appPage.Errors = append(appPage.Errors, fieldError)
+				}
+			}
+		}
+	}
+
+	return appPage
+}
+
+// returns if the list contains the value
+func containsString(list []string, value string) bool {
+	for _, entry := range list {
+		if entry == value {
+			return true
+		}
+	}
+
+	return false
+}
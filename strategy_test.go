@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMergeExtractions(t *testing.T) {
+	dom := newExtraction(strategyNameDOM)
+	dom.AppPage.Name = "Name from dom"
+	dom.track(nil, fieldName)
+	dom.track(errors.New("rating : not found"), fieldRating)
+	dom.track(errors.New("category : not found"), fieldCategory)
+	dom.PageErrors = append(dom.PageErrors, "page error")
+
+	jsonLD := newExtraction(strategyNameJSONLD)
+	jsonLD.AppPage.Name = "Name from json-ld"
+	jsonLD.AppPage.Rating = 4.2
+	jsonLD.fill(fieldName, fieldRating)
+
+	appPage := mergeExtractions(AppPage{PackageName: "com.test"}, []Extraction{dom, jsonLD})
+	if appPage.Name != "Name from dom" {
+		t.Errorf("name should be taken from the first strategy, got %q", appPage.Name)
+	}
+	if appPage.Rating != 4.2 {
+		t.Errorf("rating should fall back to json-ld, got %v", appPage.Rating)
+	}
+	if appPage.PackageName != "com.test" {
+		t.Errorf("fields which are not extracted should be kept, got %q", appPage.PackageName)
+	}
+	wantSources := map[string]string{fieldName: strategyNameDOM, fieldRating: strategyNameJSONLD}
+	if !reflect.DeepEqual(appPage.FieldSources, wantSources) {
+		t.Errorf("field sources should be %v, got %v", wantSources, appPage.FieldSources)
+	}
+	wantErrors := []string{"page error", "category : not found"}
+	if !reflect.DeepEqual(appPage.Errors, wantErrors) {
+		t.Errorf("errors should be %v, got %v", wantErrors, appPage.Errors)
+	}
+}
+
+func TestStructuredStrategies(t *testing.T) {
+	document := parseDoc(readFixture(t, "app-pages", "redesigned"))
+	options := CrawlOptions{Fetcher: NewHTTPFetcher()}.withDefaults()
+
+	jsonLD := jsonLDStrategy{}.Extract(context.Background(), document, options)
+	if jsonLD.AppPage.Name != "Spotify: Music and Podcasts" || jsonLD.AppPage.StarsCount != 24500312 || jsonLD.AppPage.Price != "free" {
+		t.Errorf("json-ld strategy extracted %+v", jsonLD.AppPage)
+	}
+
+	initData := initDataStrategy{}.Extract(context.Background(), document, options)
+	if len(initData.PageErrors) > 0 {
+		t.Fatalf("init-data strategy should not fail, got %v", initData.PageErrors)
+	}
+	if initData.AppPage.EstimatedDownloadNumber != 1000000000 || initData.AppPage.LastUpdate == nil || !initData.AppPage.LastUpdate.Equal(time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)) || !initData.AppPage.ContainsAds {
+		t.Errorf("init-data strategy extracted %+v", initData.AppPage)
+	}
+	if initData.AppPage.RealInstalls != 1523873451 || initData.AppPage.InstallBucket != "1,000,000,000+" {
+		t.Errorf("init-data strategy should extract the installs, got %d in bucket %q", initData.AppPage.RealInstalls, initData.AppPage.InstallBucket)
+	}
+	wantCountPerRating := StarCountPerRating{Five: 19110245, Four: 2450031, Three: 980012, Two: 490006, One: 1470018}
+	if initData.AppPage.CountPerRating != wantCountPerRating {
+		t.Errorf("count per rating should be %+v, got %+v", wantCountPerRating, initData.AppPage.CountPerRating)
+	}
+	wantPercentPerRating := StarPercentPerRating{Five: 78, Four: 10, Three: 4, Two: 2, One: 6}
+	if initData.AppPage.PercentPerRating != wantPercentPerRating {
+		t.Errorf("percent per rating should be %+v, got %+v", wantPercentPerRating, initData.AppPage.PercentPerRating)
+	}
+
+	// a paid app is marked as paid, the currency is only taken if the page names it
+	paid := parseDoc(strings.Replace(readFixture(t, "app-pages", "redesigned"), `"price": "0"`, `"price": "4.99"`, 1))
+	jsonLD = jsonLDStrategy{}.Extract(context.Background(), paid, options)
+	if jsonLD.AppPage.Price != "paid" || jsonLD.AppPage.PriceValue != 4.99 || !jsonLD.Filled[fieldPrice] || jsonLD.Filled[fieldPriceCurrency] {
+		t.Errorf("paid app without currency should be extracted by json-ld, got %q, %v and %v", jsonLD.AppPage.Price, jsonLD.AppPage.PriceValue, jsonLD.Filled)
+	}
+
+	malformed := parseDoc("<html><body><script>AF_initDataCallback({key: 'ds:5', data:[1,2,</script></body></html>")
+	initData = initDataStrategy{}.Extract(context.Background(), malformed, options)
+	if len(initData.PageErrors) != 1 || len(initData.Filled) != 0 {
+		t.Errorf("malformed data set should be reported as page error, got %v", initData.PageErrors)
+	}
+}
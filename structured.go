@@ -0,0 +1,433 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OlegSchmidt/soup"
+	"github.com/jehiah/go-strftime"
+)
+
+const (
+	script        = "script"
+	attributeType = "type"
+
+	// type of the script containing the schema.org description of the app
+	scriptTypeJSONLD = "application/ld+json"
+	// schema.org type of the app description
+	jsonLDTypeApp = "SoftwareApplication"
+
+	// call of the scripts containing the data the Google Play Store renders the page from
+	initDataCallback = "AF_initDataCallback("
+	// key of the data set with the details of the app
+	initDataKeyDetails = "ds:5"
+	// prices in the data sets are given in millionths of the currency
+	initDataPriceFactor = 1000000
+)
+
+// paths to the fields of the app inside the "ds:5" data set
+var (
+	initDataPathApp                     = []int{1, 2}
+	initDataPathName                    = []int{1, 2, 0, 0}
+	initDataPathDescription             = []int{1, 2, 72, 0, 1}
+	initDataPathWhatsNew                = []int{1, 2, 144, 1, 1}
+	initDataPathUsk                     = []int{1, 2, 9, 0}
+	initDataPathCategory                = []int{1, 2, 79, 0, 0, 0}
+	initDataPathRating                  = []int{1, 2, 51, 0, 1}
+	initDataPathCountPerRating          = []int{1, 2, 51, 1}
+	initDataPathStarsCount              = []int{1, 2, 51, 2, 1}
+	initDataPathEstimatedDownloadNumber = []int{1, 2, 13, 1}
+	initDataPathRealInstalls            = []int{1, 2, 13, 2}
+	initDataPathPriceValue              = []int{1, 2, 57, 0, 0, 0, 0, 1, 0, 0}
+	initDataPathPriceCurrency           = []int{1, 2, 57, 0, 0, 0, 0, 1, 0, 1}
+	initDataPathInAppPurchases          = []int{1, 2, 19, 0}
+	initDataPathContainsAds             = []int{1, 2, 48}
+	initDataPathDeveloperWebsite        = []int{1, 2, 69, 0, 5, 2}
+	initDataPathDeveloperEmail          = []int{1, 2, 69, 1, 0}
+	initDataPathDeveloperAddress        = []int{1, 2, 69, 2, 0}
+	initDataPathPrivacyPolicyURL        = []int{1, 2, 99, 0, 5, 2}
+	initDataPathOfferedBy               = []int{1, 2, 68, 0}
+	initDataPathDeveloperLink           = []int{1, 2, 68, 1, 4, 2}
+	initDataPathContentRatingDescriptor = []int{1, 2, 9, 2, 1}
+	initDataPathReleaseDate             = []int{1, 2, 10, 0}
+	initDataPathRequiresOsVersion       = []int{1, 2, 140, 1, 1, 0, 0, 1}
+	initDataPathCurrentSoftwareVersion  = []int{1, 2, 140, 0, 0, 0}
+	initDataPathIcon                    = []int{1, 2, 95, 0, 3, 2}
+	initDataPathScreenshots             = []int{1, 2, 78, 0}
+	initDataPathFeatureGraphic          = []int{1, 2, 96, 0, 3, 2}
+	initDataPathPromoVideo              = []int{1, 2, 100, 0, 0, 3, 2}
+	initDataPathLastUpdate              = []int{1, 2, 145, 0, 1, 0}
+)
+
+// jsonLDStrategy extracts the fields from the schema.org description embedded as json-ld
+type jsonLDStrategy struct{}
+
+func (strategy jsonLDStrategy) Name() string {
+	return strategyNameJSONLD
+}
+
+func (strategy jsonLDStrategy) Extract(ctx context.Context, document soup.Root, options CrawlOptions) Extraction {
+	extraction := newExtraction(strategy.Name())
+	appPage := &extraction.AppPage
+
+	app, appError := getJSONLDApp(document)
+	if appError != nil {
+		extraction.PageErrors = append(extraction.PageErrors, appError.Error())
+		return extraction
+	}
+	if app == nil {
+		return extraction
+	}
+
+	if name, isString := app["name"].(string); isString && name != "" {
+		appPage.Name = name
+		extraction.fill(fieldName)
+	}
+	if description, isString := app["description"].(string); isString && description != "" {
+		appPage.Description = strings.Replace(description, "<br>", "\n", -1)
+		extraction.fill(fieldDescription)
+	}
+	if contentRating, isString := app["contentRating"].(string); isString && contentRating != "" {
+		appPage.USK = contentRating
+		extraction.fill(fieldUsk)
+	}
+	if author, isObject := app["author"].(map[string]interface{}); isObject {
+		if website, isString := author["url"].(string); isString && website != "" {
+			appPage.DeveloperName = website
+			appPage.DeveloperWebsite = website
+			extraction.fill(fieldDeveloperName, fieldDeveloperWebsite)
+		}
+		if offeredBy, isString := author["name"].(string); isString && offeredBy != "" {
+			appPage.OfferedBy = offeredBy
+			extraction.fill(fieldOfferedBy)
+		}
+	}
+	if image, isString := app["image"].(string); isString && image != "" {
+		appPage.IconURL = image
+		extraction.fill(fieldIconURL)
+	}
+	if aggregateRating, isObject := app["aggregateRating"].(map[string]interface{}); isObject {
+		if rating, isNumber := getJSONNumber(aggregateRating["ratingValue"]); isNumber {
+			appPage.Rating = rating
+			extraction.fill(fieldRating)
+		}
+		if starsCount, isNumber := getJSONNumber(aggregateRating["ratingCount"]); isNumber {
+			appPage.StarsCount = int64(starsCount)
+			extraction.fill(fieldStarsCount)
+		}
+	}
+	// offers are either a single object or a list of them
+	offer, isObject := app["offers"].(map[string]interface{})
+	if offers, isList := app["offers"].([]interface{}); isList && len(offers) > 0 {
+		offer, isObject = offers[0].(map[string]interface{})
+	}
+	if isObject {
+		if priceValue, isNumber := getJSONNumber(offer["price"]); isNumber {
+			appPage.PriceValue = priceValue
+			appPage.Price = "paid"
+			if priceValue == 0 {
+				appPage.Price = "free"
+			}
+			extraction.fill(fieldPrice, fieldPriceValue)
+			if priceCurrency, isString := offer["priceCurrency"].(string); isString && priceCurrency != "" && priceValue != 0 {
+				appPage.PriceCurrency = priceCurrency
+				extraction.fill(fieldPriceCurrency)
+			}
+		}
+	}
+
+	return extraction
+}
+
+// returns the first schema.org description of an app, nil if the page doesn't contain one
+func getJSONLDApp(document soup.Root) (map[string]interface{}, error) {
+	for _, scriptElement := range document.FindAll(script, attributeType, scriptTypeJSONLD) {
+		var description map[string]interface{}
+		decodeError := json.Unmarshal([]byte(scriptElement.Text()), &description)
+		if decodeError != nil {
+			return nil, errors.New("json-ld : <script type=\"" + scriptTypeJSONLD + "\"></script> doesn't contain valid json : " + decodeError.Error())
+		}
+		if description["@type"] == jsonLDTypeApp {
+			return description, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// initDataStrategy extracts the fields from the data sets the Google Play Store passes to AF_initDataCallback
+type initDataStrategy struct{}
+
+func (strategy initDataStrategy) Name() string {
+	return strategyNameInitData
+}
+
+func (strategy initDataStrategy) Extract(ctx context.Context, document soup.Root, options CrawlOptions) Extraction {
+	extraction := newExtraction(strategy.Name())
+	appPage := &extraction.AppPage
+
+	details, detailsError := getInitData(document, initDataKeyDetails)
+	if detailsError != nil {
+		extraction.PageErrors = append(extraction.PageErrors, detailsError.Error())
+		return extraction
+	}
+	if details == nil {
+		return extraction
+	}
+
+	if name, isString := getInitDataValue(details, initDataPathName).(string); isString && name != "" {
+		appPage.Name = name
+		extraction.fill(fieldName)
+	}
+	if description, isString := getInitDataValue(details, initDataPathDescription).(string); isString && description != "" {
+		appPage.Description = strings.Replace(description, "<br>", "\n", -1)
+		extraction.fill(fieldDescription)
+	}
+	if whatsNew, isString := getInitDataValue(details, initDataPathWhatsNew).(string); isString && whatsNew != "" {
+		appPage.WhatsNew = strings.Split(strings.Replace(whatsNew, "<br>", "\n", -1), "\n")
+		extraction.fill(fieldWhatsNew)
+	}
+	if usk, isString := getInitDataValue(details, initDataPathUsk).(string); isString && usk != "" {
+		appPage.USK = usk
+		extraction.fill(fieldUsk)
+	}
+	if category, isString := getInitDataValue(details, initDataPathCategory).(string); isString && category != "" {
+		appPage.Category = category
+		extraction.fill(fieldCategory)
+	}
+	if rating, isNumber := getJSONNumber(getInitDataValue(details, initDataPathRating)); isNumber {
+		appPage.Rating = rating
+		extraction.fill(fieldRating)
+	}
+	if starsCount, isNumber := getJSONNumber(getInitDataValue(details, initDataPathStarsCount)); isNumber {
+		appPage.StarsCount = int64(starsCount)
+		extraction.fill(fieldStarsCount)
+	}
+	if histogram, isList := getInitDataValue(details, initDataPathCountPerRating).([]interface{}); isList {
+		countPerRating, countPerRatingError := getInitDataCountPerRating(histogram)
+		if countPerRatingError == nil {
+			appPage.CountPerRating = countPerRating
+			appPage.PercentPerRating = getPercentPerRating(countPerRating)
+			extraction.fill(fieldCountPerRating, fieldPercentPerRating)
+		}
+	}
+	if downloads, isNumber := getJSONNumber(getInitDataValue(details, initDataPathEstimatedDownloadNumber)); isNumber {
+		appPage.EstimatedDownloadNumber = int64(downloads)
+		appPage.InstallBucket = getInstallBucket(appPage.EstimatedDownloadNumber)
+		extraction.fill(fieldEstimatedDownloadNumber, fieldInstallBucket)
+	}
+	if realInstalls, isNumber := getJSONNumber(getInitDataValue(details, initDataPathRealInstalls)); isNumber {
+		appPage.RealInstalls = int64(realInstalls)
+		extraction.fill(fieldRealInstalls)
+	}
+	if priceMicros, isNumber := getJSONNumber(getInitDataValue(details, initDataPathPriceValue)); isNumber {
+		appPage.PriceValue = priceMicros / initDataPriceFactor
+		appPage.Price = "paid"
+		if appPage.PriceValue == 0 {
+			appPage.Price = "free"
+		}
+		extraction.fill(fieldPrice, fieldPriceValue)
+		if priceCurrency, isString := getInitDataValue(details, initDataPathPriceCurrency).(string); isString && priceCurrency != "" && appPage.PriceValue != 0 {
+			appPage.PriceCurrency = priceCurrency
+			extraction.fill(fieldPriceCurrency)
+		}
+	}
+	// the labels for in-app purchases and ads are null if the app doesn't have them
+	if getInitDataValue(details, initDataPathApp) != nil {
+		inAppPurchases, _ := getInitDataValue(details, initDataPathInAppPurchases).(string)
+		appPage.InAppPurchases = inAppPurchases != ""
+		containsAds, _ := getInitDataValue(details, initDataPathContainsAds).(bool)
+		appPage.ContainsAds = containsAds
+		extraction.fill(fieldInAppPurchases, fieldContainsAds)
+	}
+	if website, isString := getInitDataValue(details, initDataPathDeveloperWebsite).(string); isString && website != "" {
+		appPage.DeveloperName = website
+		appPage.DeveloperWebsite = website
+		extraction.fill(fieldDeveloperName, fieldDeveloperWebsite)
+	}
+	if email, isString := getInitDataValue(details, initDataPathDeveloperEmail).(string); isString && email != "" {
+		appPage.DeveloperEmail = email
+		extraction.fill(fieldDeveloperEmail)
+	}
+	if address, isString := getInitDataValue(details, initDataPathDeveloperAddress).(string); isString && address != "" {
+		appPage.DeveloperAddress = address
+		extraction.fill(fieldDeveloperAddress)
+	}
+	if privacyPolicyURL, isString := getInitDataValue(details, initDataPathPrivacyPolicyURL).(string); isString && privacyPolicyURL != "" {
+		appPage.PrivacyPolicyURL = privacyPolicyURL
+		extraction.fill(fieldPrivacyPolicyURL)
+	}
+	if developerLink, isString := getInitDataValue(details, initDataPathDeveloperLink).(string); isString {
+		if developerID, isDeveloperLink := getDeveloperIDFromLink(developerLink); isDeveloperLink {
+			appPage.DeveloperID = developerID
+			extraction.fill(fieldDeveloperID)
+		}
+	}
+	if offeredBy, isString := getInitDataValue(details, initDataPathOfferedBy).(string); isString && offeredBy != "" {
+		appPage.OfferedBy = offeredBy
+		extraction.fill(fieldOfferedBy)
+	}
+	if descriptor, isString := getInitDataValue(details, initDataPathContentRatingDescriptor).(string); isString && descriptor != "" {
+		appPage.ContentRatingDescriptors = []string{descriptor}
+		extraction.fill(fieldContentRatingDescriptors)
+	}
+	if releaseDateString, isString := getInitDataValue(details, initDataPathReleaseDate).(string); isString {
+		if releaseDate, releaseDateError := getLocale(options.Language).parseDate(releaseDateString); releaseDateError == nil {
+			appPage.ReleaseDate = &releaseDate
+			extraction.fill(fieldReleaseDate)
+		}
+	}
+	if requiresOsVersion, isString := getInitDataValue(details, initDataPathRequiresOsVersion).(string); isString && requiresOsVersion != "" {
+		appPage.RequiresOsVersion = requiresOsVersion
+		extraction.fill(fieldRequiresOsVersion)
+	}
+	if currentSoftwareVersion, isString := getInitDataValue(details, initDataPathCurrentSoftwareVersion).(string); isString && currentSoftwareVersion != "" {
+		appPage.CurrentSoftwareVersion = currentSoftwareVersion
+		extraction.fill(fieldCurrentSoftwareVersion)
+	}
+	if lastUpdateSeconds, isNumber := getJSONNumber(getInitDataValue(details, initDataPathLastUpdate)); isNumber {
+		lastUpdate := time.Unix(int64(lastUpdateSeconds), 0).UTC()
+		appPage.LastUpdate = &lastUpdate
+		extraction.fill(fieldLastUpdate)
+	}
+	if iconURL, isString := getInitDataValue(details, initDataPathIcon).(string); isString && iconURL != "" {
+		appPage.IconURL = iconURL
+		extraction.fill(fieldIconURL)
+	}
+	if screenshotList, isList := getInitDataValue(details, initDataPathScreenshots).([]interface{}); isList {
+		appPage.Screenshots = getInitDataScreenshots(screenshotList)
+		extraction.fill(fieldScreenshots)
+	}
+	if featureGraphicURL, isString := getInitDataValue(details, initDataPathFeatureGraphic).(string); isString && featureGraphicURL != "" {
+		appPage.FeatureGraphicURL = featureGraphicURL
+		extraction.fill(fieldFeatureGraphicURL)
+	}
+	if promoVideoURL, isString := getInitDataValue(details, initDataPathPromoVideo).(string); isString {
+		if promoVideoID := getYouTubeVideoID(promoVideoURL); promoVideoID != "" {
+			appPage.PromoVideoID = promoVideoID
+			extraction.fill(fieldPromoVideoID)
+		}
+	}
+
+	return extraction
+}
+
+// returns the data set with the given key passed to AF_initDataCallback, nil if the page doesn't contain it
+func getInitData(document soup.Root, key string) ([]interface{}, error) {
+	for _, scriptElement := range document.FindAll(script) {
+		scriptText := scriptElement.Text()
+		for _, call := range strings.Split(scriptText, initDataCallback)[1:] {
+			dataPosition := strings.Index(call, "data:")
+			if dataPosition < 0 || !isInitDataKey(call[:dataPosition], key) {
+				continue
+			}
+			listPosition := strings.Index(call[dataPosition:], "[")
+			if listPosition < 0 {
+				return nil, errors.New("init-data : data set \"" + key + "\" doesn't contain a list")
+			}
+			var data []interface{}
+			decoder := json.NewDecoder(strings.NewReader(call[dataPosition+listPosition:]))
+			decodeError := decoder.Decode(&data)
+			if decodeError != nil {
+				return nil, errors.New("init-data : data set \"" + key + "\" doesn't contain valid json : " + decodeError.Error())
+			}
+			return data, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// returns if the options of the callback, given up to the data, name the key
+func isInitDataKey(callOptions string, key string) bool {
+	return strings.Contains(callOptions, "'"+key+"'") || strings.Contains(callOptions, "\""+key+"\"")
+}
+
+// returns the value at the path inside the nested lists of a data set, nil if the path doesn't exist
+func getInitDataValue(data []interface{}, path []int) interface{} {
+	var value interface{} = data
+	for _, position := range path {
+		list, isList := value.([]interface{})
+		if !isList || position >= len(list) {
+			return nil
+		}
+		value = list[position]
+	}
+
+	return value
+}
+
+// returns the number of ratings with each amount of stars, the histogram lists it as second value for each star
+func getInitDataCountPerRating(histogram []interface{}) (StarCountPerRating, error) {
+	var countPerRating StarCountPerRating
+	var counts [6]int64
+	for stars := 1; stars <= 5; stars++ {
+		count, isNumber := getJSONNumber(getInitDataValue(histogram, []int{stars, 1}))
+		if !isNumber {
+			return countPerRating, errors.New("count_per_rating : histogram doesn't contain the number of ratings with " + strconv.Itoa(stars) + " stars")
+		}
+		counts[stars] = int64(count)
+	}
+	countPerRating.Five = counts[5]
+	countPerRating.Four = counts[4]
+	countPerRating.Three = counts[3]
+	countPerRating.Two = counts[2]
+	countPerRating.One = counts[1]
+
+	return countPerRating, nil
+}
+
+// returns the screenshots of the data set, each entry holds the width and height of the screenshot and its url
+func getInitDataScreenshots(screenshotList []interface{}) []Screenshot {
+	screenshots := []Screenshot{}
+	for _, entry := range screenshotList {
+		entryList, isList := entry.([]interface{})
+		if !isList {
+			continue
+		}
+		screenshotURL, isString := getInitDataValue(entryList, []int{3, 2}).(string)
+		if !isString || screenshotURL == "" {
+			continue
+		}
+		screenshot := Screenshot{URL: screenshotURL}
+		if width, isNumber := getJSONNumber(getInitDataValue(entryList, []int{2, 0})); isNumber {
+			screenshot.Width = int(width)
+		}
+		if height, isNumber := getJSONNumber(getInitDataValue(entryList, []int{2, 1})); isNumber {
+			screenshot.Height = int(height)
+		}
+		screenshots = append(screenshots, screenshot)
+	}
+
+	return screenshots
+}
+
+// returns the day of the unix timestamp formatted like the other dates of the crawler, e.g. 20181231
+func getDateNumber(seconds float64) int64 {
+	dateFormatted := strftime.Format("%Y%m%d", time.Unix(int64(seconds), 0).UTC())
+	date, _ := strconv.ParseInt(dateFormatted, 10, 64)
+
+	return date
+}
+
+// returns the time in UTC of the unix timestamp
+func getUnixTime(seconds float64) time.Time {
+	return time.Unix(int64(seconds), 0).UTC()
+}
+
+// returns the number of a decoded json value, numbers are sometimes given as strings
+func getJSONNumber(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case float64:
+		return number, true
+	case string:
+		parsed, parseError := strconv.ParseFloat(number, 64)
+		return parsed, parseError == nil
+	}
+
+	return 0, false
+}
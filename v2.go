@@ -0,0 +1,192 @@
+package main
+
+import "time"
+
+// paths of the fields of version 2 of the api which are named or nested differently than in the app page, used to
+// translate the keys of the field sources. Fields with an empty path are not part of version 2
+var fieldPathsV2 = map[string]string{
+	fieldUsk:                     "content_rating",
+	fieldPrice:                   "pricing.free",
+	fieldPriceValue:              "pricing.price",
+	fieldPriceCurrency:           "pricing.currency",
+	fieldInAppPurchases:          "pricing.in_app_purchases",
+	fieldInAppProducts:           "pricing.in_app_products",
+	fieldContainsAds:             "pricing.contains_ads",
+	fieldRating:                  "ratings.average",
+	fieldStarsCount:              "ratings.count",
+	fieldCountPerRating:          "ratings.count_per_rating",
+	fieldPercentPerRating:        "ratings.percent_per_rating",
+	fieldEstimatedDownloadNumber: "installs.minimum",
+	fieldRealInstalls:            "installs.exact",
+	fieldInstallBucket:           "installs.bucket",
+	fieldDeveloperID:             "developer.id",
+	// the "developer" of the app page is the link to the website of the developer, not its name
+	fieldDeveloperName:     "",
+	fieldOfferedBy:         "developer.name",
+	fieldDeveloperWebsite:  "developer.website",
+	fieldDeveloperEmail:    "developer.email",
+	fieldDeveloperAddress:  "developer.address",
+	fieldTopDeveloper:      "developer.top_developer",
+	fieldIconURL:           "media.icon_url",
+	fieldScreenshots:       "media.screenshots",
+	fieldFeatureGraphicURL: "media.feature_graphic_url",
+	fieldPromoVideoID:      "media.promo_video_id",
+}
+
+// AppPageV2 model, the app page returned by version 2 of the api. The fields are named consistently and the ones
+// belonging together are nested
+type AppPageV2 struct {
+	PackageName              string            `json:"package_name"`
+	Name                     string            `json:"name"`
+	DateCrawled              time.Time         `json:"date_crawled"`
+	Language                 string            `json:"language"`
+	Country                  string            `json:"country"`
+	SelectorProfileVersion   string            `json:"selector_profile_version"`
+	Category                 string            `json:"category"`
+	ContentRating            string            `json:"content_rating"`
+	ContentRatingDescriptors []string          `json:"content_rating_descriptors"`
+	InteractiveElements      []string          `json:"interactive_elements"`
+	Description              string            `json:"description"`
+	WhatsNew                 []string          `json:"whats_new"`
+	Pricing                  PricingV2         `json:"pricing"`
+	Ratings                  RatingsV2         `json:"ratings"`
+	Installs                 InstallsV2        `json:"installs"`
+	Developer                DeveloperV2       `json:"developer"`
+	LastUpdate               *time.Time        `json:"last_update"`
+	ReleaseDate              *time.Time        `json:"release_date"`
+	Os                       string            `json:"os"`
+	RequiresOsVersion        string            `json:"requires_os_version"`
+	CurrentSoftwareVersion   string            `json:"current_software_version"`
+	Size                     string            `json:"size"`
+	PrivacyPolicyURL         string            `json:"privacy_policy_url"`
+	DataSafety               DataSafety        `json:"data_safety"`
+	Permissions              []PermissionGroup `json:"permissions"`
+	Media                    MediaV2           `json:"media"`
+	SimilarApps              []string          `json:"similar_apps"`
+	Blocked                  bool              `json:"blocked"`
+	// keys are the paths of the fields like "pricing.price"
+	FieldSources map[string]string `json:"field_sources"`
+	Errors       []string          `json:"errors"`
+}
+
+// PricingV2 model, the price is zero for free apps and if it is not known
+type PricingV2 struct {
+	Free           bool    `json:"free"`
+	Price          float64 `json:"price"`
+	Currency       string  `json:"currency"`
+	InAppPurchases bool    `json:"in_app_purchases"`
+	InAppProducts  string  `json:"in_app_products"`
+	ContainsAds    bool    `json:"contains_ads"`
+}
+
+// RatingsV2 model
+type RatingsV2 struct {
+	Average          float64              `json:"average"`
+	Count            int64                `json:"count"`
+	CountPerRating   StarCountPerRating   `json:"count_per_rating"`
+	PercentPerRating StarPercentPerRating `json:"percent_per_rating"`
+}
+
+// InstallsV2 model, the exact number is zero if the page doesn't contain it
+type InstallsV2 struct {
+	Minimum int64  `json:"minimum"`
+	Exact   int64  `json:"exact"`
+	Bucket  string `json:"bucket"`
+}
+
+// DeveloperV2 model
+type DeveloperV2 struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Website      string `json:"website"`
+	Email        string `json:"email"`
+	Address      string `json:"address"`
+	TopDeveloper bool   `json:"top_developer"`
+}
+
+// MediaV2 model, the files are only set if the app page was requested with "download_media"
+type MediaV2 struct {
+	IconURL           string       `json:"icon_url"`
+	Screenshots       []Screenshot `json:"screenshots"`
+	FeatureGraphicURL string       `json:"feature_graphic_url"`
+	PromoVideoID      string       `json:"promo_video_id"`
+	Files             []MediaFile  `json:"files"`
+}
+
+// returns the app page in the shape of version 2 of the api
+func (appPage AppPage) v2() AppPageV2 {
+	appPageV2 := AppPageV2{
+		PackageName:              appPage.PackageName,
+		Name:                     appPage.Name,
+		DateCrawled:              appPage.DateCrawled,
+		Language:                 appPage.Language,
+		Country:                  appPage.Country,
+		SelectorProfileVersion:   appPage.SelectorProfileVersion,
+		Category:                 appPage.Category,
+		ContentRating:            appPage.USK,
+		ContentRatingDescriptors: appPage.ContentRatingDescriptors,
+		InteractiveElements:      appPage.InteractiveElements,
+		Description:              appPage.Description,
+		WhatsNew:                 appPage.WhatsNew,
+		Pricing: PricingV2{
+			Free:           appPage.Price == "free",
+			Price:          appPage.PriceValue,
+			Currency:       appPage.PriceCurrency,
+			InAppPurchases: appPage.InAppPurchases,
+			InAppProducts:  appPage.InAppProducts,
+			ContainsAds:    appPage.ContainsAds,
+		},
+		Ratings: RatingsV2{
+			Average:          appPage.Rating,
+			Count:            appPage.StarsCount,
+			CountPerRating:   appPage.CountPerRating,
+			PercentPerRating: appPage.PercentPerRating,
+		},
+		Installs: InstallsV2{
+			Minimum: appPage.EstimatedDownloadNumber,
+			Exact:   appPage.RealInstalls,
+			Bucket:  appPage.InstallBucket,
+		},
+		Developer: DeveloperV2{
+			ID:           appPage.DeveloperID,
+			Name:         appPage.OfferedBy,
+			Website:      appPage.DeveloperWebsite,
+			Email:        appPage.DeveloperEmail,
+			Address:      appPage.DeveloperAddress,
+			TopDeveloper: appPage.TopDeveloper,
+		},
+		LastUpdate:             appPage.LastUpdate,
+		ReleaseDate:            appPage.ReleaseDate,
+		Os:                     appPage.Os,
+		RequiresOsVersion:      appPage.RequiresOsVersion,
+		CurrentSoftwareVersion: appPage.CurrentSoftwareVersion,
+		Size:                   appPage.Size,
+		PrivacyPolicyURL:       appPage.PrivacyPolicyURL,
+		DataSafety:             appPage.DataSafety,
+		Permissions:            appPage.Permissions,
+		Media: MediaV2{
+			IconURL:           appPage.IconURL,
+			Screenshots:       appPage.Screenshots,
+			FeatureGraphicURL: appPage.FeatureGraphicURL,
+			PromoVideoID:      appPage.PromoVideoID,
+			Files:             appPage.MediaFiles,
+		},
+		SimilarApps: appPage.SimilarApps,
+		Blocked:     appPage.Blocked,
+		Errors:      appPage.Errors,
+	}
+	if appPage.FieldSources != nil {
+		appPageV2.FieldSources = make(map[string]string, len(appPage.FieldSources))
+		for field, source := range appPage.FieldSources {
+			if path, renamed := fieldPathsV2[field]; renamed {
+				if path == "" {
+					continue
+				}
+				field = path
+			}
+			appPageV2.FieldSources[field] = source
+		}
+	}
+
+	return appPageV2
+}